@@ -12,6 +12,8 @@ import (
 	"github.com/chunkifydev/chunkify-go/option"
 	chunkifyCmd "github.com/chunkifydev/cli/pkg/chunkify"
 	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/sources"
+	"github.com/chunkifydev/cli/pkg/storages"
 	"github.com/chunkifydev/cli/pkg/version"
 	"github.com/chunkifydev/cli/pkg/webhook"
 	"github.com/spf13/cobra"
@@ -45,7 +47,8 @@ func Execute() {
 	// Check for updates after each command
 	// TODO: check updates less often
 	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
-		if cmd.Name() == "update" {
+		// the webhook commands are local, they must work offline
+		if cmd.Name() == "update" || (cmd.Parent() != nil && cmd.Parent().Name() == "webhook") {
 			return
 		}
 		upToDate, latestVersion := version.IsUpToDate()
@@ -63,8 +66,8 @@ func Execute() {
 
 // initChunkifyClient verifies authentication tokens and initializes the Chunkify client.
 func initChunkifyClient(cmd *cobra.Command, args []string) {
-	// All commands require project token, except config
-	if cmd.Name() != "config" {
+	// All commands require project token, except config, preset and webhook which are local
+	if cmd.Name() != "config" && !(cmd.Parent() != nil && slices.Contains([]string{"preset", "webhook"}, cmd.Parent().Name())) {
 		if cfg.Token == "" {
 			if err := cfg.SetToken(); err != nil {
 				fmt.Printf("Authentication issue\n\n")
@@ -107,7 +110,20 @@ func init() {
 	}
 
 	rootCmd = chunkifyCmd.NewCommand(cfg).Command
+	rootCmd.AddCommand(chunkifyCmd.NewBatchCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewWatchCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewRunCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewPresetCommand(cfg).Command)
+	rootCmd.AddCommand(sources.NewCommand(cfg).Command)
+	rootCmd.AddCommand(storages.NewCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewJobsCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewFilesCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewUploadCommand(cfg).Command)
+	rootCmd.AddCommand(chunkifyCmd.NewUploadsCommand(cfg).Command)
 	rootCmd.AddCommand(webhook.NewCommand(cfg).Command)
+	rootCmd.AddCommand(webhook.NewWebhooksCommand(cfg).Command)
+	rootCmd.AddCommand(webhook.NewNotificationsCommand(cfg).Command)
+	rootCmd.AddCommand(webhook.NewWebhookCommand(cfg).Command)
 	rootCmd.AddCommand(VersionCmd)
 	rootCmd.AddCommand(CliUpdateCmd)
 	rootCmd.AddCommand(config.NewCommand())
@@ -7,6 +7,7 @@ require (
 	github.com/chunkifydev/chunkify-go v0.6.0
 	github.com/spf13/cobra v1.8.1
 	golang.org/x/text v0.18.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -39,6 +40,6 @@ require (
 	github.com/charmbracelet/lipgloss v0.13.0
 	github.com/google/uuid v1.6.0
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.5
 	github.com/zalando/go-keyring v0.2.6-0.20240923113553-ead676fd21b7
 )
@@ -0,0 +1,535 @@
+package chunkify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// videoExtensions are the files picked up when an input is a directory
+var videoExtensions = map[string]bool{
+	".mp4":  true,
+	".m4v":  true,
+	".mov":  true,
+	".mkv":  true,
+	".webm": true,
+	".avi":  true,
+	".flv":  true,
+	".wmv":  true,
+	".mpg":  true,
+	".mpeg": true,
+	".ts":   true,
+	".mts":  true,
+	".m2ts": true,
+	".mxf":  true,
+	".3gp":  true,
+}
+
+// BatchCommand transcodes many inputs with the same settings
+type BatchCommand struct {
+	ListFile    string // File with one input per line
+	Recursive   bool   // Walk directories recursively
+	OutDir      string // Directory where the outputs are downloaded
+	Output      string // Output template of each input, relative to OutDir
+	Concurrency int    // Number of inputs processed at the same time
+}
+
+// BatchResult is the outcome of the pipeline of one input
+type BatchResult struct {
+	Input    string
+	Output   string
+	SourceID string
+	JobID    string
+	Detached bool // the job was created without waiting for it
+	Skipped  bool // the batch was interrupted before the input was started
+	Err      error
+}
+
+func NewBatchCommand(cfg *config.Config) *Command {
+	app := NewApp()
+	batch := &BatchCommand{}
+
+	cmd := &Command{
+		App:    app,
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "batch [inputs...]",
+			Short: "Transcode many videos with the same settings",
+			Long: `Transcode many videos with the same settings
+
+Inputs can be files, globs, directories, HTTP URLs or source IDs (src_*), given as arguments or listed in a file with --list (one per line, # for comments).
+Each input is uploaded, transcoded and downloaded into --out, named after the input.
+Use -o to name the outputs with placeholders: {input_name}, {height}, {format}, {ext}, {job_id}, {source_id}, {date} and {profile}.
+
+Examples:
+
+Transcode all the videos of a folder
+chunkify batch videos/ --out transcoded/ -f mp4_h264 -s 1280x720 --crf 23
+
+Transcode the files matching a glob, 4 at a time
+chunkify batch "raw/*.mov" --out hls/ -f hls_h264 --ladder 1080p:5M,720p:2.5M --concurrency 4
+
+Transcode the inputs listed in a file
+chunkify batch --list inputs.txt --out transcoded/ -f webm_vp9
+
+Name the outputs after the input and the date
+chunkify batch videos/ --out transcoded/ -o "{date}/{input_name}_{height}p.{ext}" -f mp4_h264 -s 1280x720
+`,
+			Args: func(cmd *cobra.Command, args []string) error {
+				if len(args) == 0 && batch.ListFile == "" {
+					return fmt.Errorf("no inputs: pass files, globs or directories as arguments, or use --list")
+				}
+				return nil
+			},
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				if batch.Concurrency < 1 || batch.Concurrency > 32 {
+					return fmt.Errorf("--concurrency must be between 1 and 32")
+				}
+				if (batch.OutDir != "" || batch.Output != "") && app.Command.Detach {
+					return fmt.Errorf("--out and --output can't be used with --detach, the outputs are downloaded with chunkify files download")
+				}
+				if (batch.OutDir != "" || batch.Output != "") && app.Command.NoDownload {
+					return fmt.Errorf("--out and --output can't be used with --no-download, the outputs stay on the storage")
+				}
+				if err := validateBatchOutput(batch.Output); err != nil {
+					return err
+				}
+				if err := prepareCommand(app, cmd.Flags()); err != nil {
+					return err
+				}
+				// the format may come from --use-preset, applied by prepareCommand
+				if (batch.OutDir != "" || batch.Output != "") && app.Command.Format == "" {
+					return fmt.Errorf("--format is required when --out or --output is set")
+				}
+				return nil
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				app.Client = cfg.Client
+				app.Command.Profile = cfg.Profile
+
+				inputs, err := expandInputs(args, batch.ListFile, batch.Recursive)
+				if err != nil {
+					fmt.Printf("Error: %s\n", err)
+					os.Exit(1)
+				}
+				if len(inputs) == 0 {
+					fmt.Println("Error: no video found in the given inputs")
+					os.Exit(1)
+				}
+
+				fmt.Printf("  Processing %d inputs, %d at a time\n\n", len(inputs), batch.Concurrency)
+
+				ctx, stop := notifyPipelineInterrupts(context.Background(), app.Command.OnInterrupt)
+				results := batch.Run(ctx, app, inputs)
+				stop()
+
+				fmt.Println()
+				printBatchResults(os.Stdout, results)
+
+				for _, r := range results {
+					if r.Err != nil {
+						os.Exit(1)
+					}
+				}
+			},
+		},
+	}
+
+	app.Command = &ChunkifyCommand{}
+	cmd.Command.Flags().StringVar(&batch.ListFile, "list", "", "File listing the inputs, one per line")
+	cmd.Command.Flags().BoolVarP(&batch.Recursive, "recursive", "R", false, "Look for videos in subdirectories of directory inputs")
+	cmd.Command.Flags().StringVar(&batch.OutDir, "out", "", "Directory where the outputs are downloaded. When not set, the videos are only transcoded")
+	cmd.Command.Flags().StringVarP(&batch.Output, "output", "o", "", "Output path template of each input, relative to --out (e.g. {input_name}_{height}p.{ext})")
+	cmd.Command.Flags().IntVar(&batch.Concurrency, "concurrency", 2, "Number of inputs processed at the same time (1-32)")
+	cmd.Command.Flags().BoolVar(&app.Command.Detach, "detach", false, "Create the jobs without waiting for them. Their files can be downloaded later with chunkify files download")
+	bindOnInterruptFlag(app, cmd.Command)
+	bindTranscodeFlags(app, cmd.Command)
+
+	return cmd
+}
+
+// Run processes the inputs with the settings of the template app, batch.Concurrency at a time.
+// The results are in the same order as the inputs
+func (b *BatchCommand) Run(ctx context.Context, template *App, inputs []string) []BatchResult {
+	outputs := batchOutputs(inputs, b.OutDir, b.Output, template.Command.Format)
+	results := make([]BatchResult, len(inputs))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(b.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = processInput(ctx, template, inputs[i], outputs[i])
+
+				if results[i].Err != nil {
+					slog.Error("Batch input failed", "input", inputs[i], "error", results[i].Err)
+					fmt.Printf("  %s %s: %s\n", errorText("✗"), inputs[i], results[i].Err)
+				} else {
+					fmt.Printf("  %s %s\n", completedIcon, inputs[i])
+				}
+			}
+		}()
+	}
+
+	for i := range inputs {
+		// the inputs left are skipped once the batch is interrupted
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case queue <- i:
+				continue
+			}
+		}
+		results[i] = BatchResult{Input: inputs[i], Skipped: true, Err: fmt.Errorf("not started, the batch was interrupted")}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// processInput runs the pipeline of one input with the settings of the template app
+func processInput(ctx context.Context, template *App, input string, output string) BatchResult {
+	app := newPipelineApp(template, input, output)
+	final := app.runPipeline(ctx)
+
+	result := BatchResult{Input: input, Output: final.Command.Output, Detached: app.Command.Detach, Err: final.Error}
+	// the outputs stay on the storage
+	if app.Command.NoDownload {
+		result.Output = filesLocation(final.Files)
+	}
+	if result.Err == nil && ctx.Err() != nil {
+		// the pipeline was interrupted
+		switch final.Status {
+		case Cancelled:
+			result.Err = fmt.Errorf("interrupted, the job was cancelled")
+		case Detached:
+			result.Err = fmt.Errorf("interrupted, the job is still running on Chunkify")
+		default:
+			result.Err = ctx.Err()
+		}
+	}
+
+	// the workflow sets the source and the jobs on the app, even when their progress isn't followed
+	if app.Source != nil {
+		result.SourceID = app.Source.ID
+	}
+	if app.Job != nil {
+		result.JobID = app.Job.ID
+	}
+	if len(app.Jobs) > 0 {
+		ids := []string{}
+		for _, job := range app.Jobs {
+			ids = append(ids, job.ID)
+		}
+		result.JobID = strings.Join(ids, ",")
+	}
+
+	return result
+}
+
+// newPipelineApp returns an app processing input with the same settings as the template
+func newPipelineApp(template *App, input string, output string) *App {
+	command := *template.Command
+	command.Id = uuid.New().String()
+	command.Input = input
+	command.Output = output
+
+	// each rendition playlist is written next to the output
+	command.Renditions = make([]Rendition, len(template.Command.Renditions))
+	for i, r := range template.Command.Renditions {
+		r.Output = renditionOutput(output, r.Name)
+		command.Renditions[i] = r
+	}
+
+	app := NewApp()
+	app.Client = template.Client
+	app.Command = &command
+	return app
+}
+
+// runPipeline runs the workflow without the TUI and returns the app in its final state.
+// When ctx is cancelled, the running jobs are handled by interruptPipeline
+func (app *App) runPipeline(ctx context.Context) App {
+	pipelineCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	app.Ctx = pipelineCtx
+	app.CancelFunc = cancel
+
+	go app.executeWorkflow(pipelineCtx)
+
+	t, done := *app, false
+	for !done {
+		if t, done = t.checkChannels(); !done {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if ctx.Err() != nil {
+		t = t.interruptPipeline()
+	}
+	return t
+}
+
+// expandInputs resolves the arguments and the lines of the list file into inputs.
+// URLs and source IDs are kept as is, globs and directories are expanded into video files
+func expandInputs(args []string, listFile string, recursive bool) ([]string, error) {
+	if listFile != "" {
+		lines, err := readListFile(listFile)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, lines...)
+	}
+
+	inputs := []string{}
+	seen := map[string]bool{}
+	add := func(input string) {
+		if !seen[input] {
+			seen[input] = true
+			inputs = append(inputs, input)
+		}
+	}
+
+	for _, arg := range args {
+		if isRemoteInput(arg) {
+			add(arg)
+			continue
+		}
+
+		paths := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob %s: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no file matches %s", arg)
+			}
+			paths = matches
+		}
+
+		for _, p := range paths {
+			info, err := os.Stat(p)
+			if err != nil {
+				return nil, fmt.Errorf("file not found: %s", p)
+			}
+
+			if !info.IsDir() {
+				add(p)
+				continue
+			}
+
+			files, err := videosInDir(p, recursive)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				add(f)
+			}
+		}
+	}
+
+	return inputs, nil
+}
+
+// readListFile returns the inputs listed in a file, skipping empty lines and # comments
+func readListFile(listFile string) ([]string, error) {
+	f, err := os.Open(listFile)
+	if err != nil {
+		return nil, fmt.Errorf("open list file: %w", err)
+	}
+	defer f.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read list file: %w", err)
+	}
+
+	return lines, nil
+}
+
+// videosInDir returns the video files of dir, sorted by path. Hidden files and directories are skipped
+func videosInDir(dir string, recursive bool) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if videoExtensions[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read directory %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+// isRemoteInput returns true if the input is an HTTP URL or a source ID
+func isRemoteInput(input string) bool {
+	return strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "src_")
+}
+
+// validateBatchOutput checks that the output template gives a different path to each input
+func validateBatchOutput(output string) error {
+	if output == "" {
+		return nil
+	}
+	if err := validateOutputTemplate(output); err != nil {
+		return err
+	}
+	// the ladder is parsed later by prepareCommand, with the output of each input
+	if ladder != nil && *ladder != "" {
+		if err := validateLadderOutput(output); err != nil {
+			return err
+		}
+	}
+	for _, p := range []string{"{input_name}", "{source_id}", "{job_id}"} {
+		if strings.Contains(output, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("--output must contain {input_name}, {source_id} or {job_id} so the outputs don't collide")
+}
+
+// batchOutputs returns the output path of each input in outDir, named after the input.
+// HLS and JPG outputs produce many files, so each of them gets its own directory.
+// With a template, {input_name} is resolved here, the other placeholders are resolved by the pipeline.
+// No output is set if outDir and template are empty
+func batchOutputs(inputs []string, outDir string, template string, format string) []string {
+	outputs := make([]string, len(inputs))
+	if outDir == "" && template == "" {
+		return outputs
+	}
+
+	ext := formatExt(format)
+	used := map[string]int{}
+
+	for i, input := range inputs {
+		name := inputName(input)
+
+		// two inputs with the same name in different directories
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, used[name])
+		}
+
+		if template != "" {
+			outputs[i] = filepath.Join(outDir, strings.ReplaceAll(template, "{input_name}", name))
+		} else if strings.HasPrefix(format, "hls") || format == FormatJpg {
+			outputs[i] = filepath.Join(outDir, name, name+ext)
+		} else {
+			outputs[i] = filepath.Join(outDir, name+ext)
+		}
+	}
+
+	return outputs
+}
+
+// inputName returns the name of the input without its extension
+func inputName(input string) string {
+	base := input
+	if strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://") {
+		if u, err := url.Parse(input); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+			base = path.Base(u.Path)
+		} else {
+			base = "video"
+		}
+	} else {
+		base = filepath.Base(input)
+	}
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// formatExt returns the extension of the main output file of a format
+func formatExt(format string) string {
+	switch {
+	case strings.HasPrefix(format, "hls"):
+		return ".m3u8"
+	case strings.HasPrefix(format, "webm"):
+		return ".webm"
+	case format == FormatJpg:
+		return ".jpg"
+	default:
+		return ".mp4"
+	}
+}
+
+// printBatchResults prints a table with the result of each input
+func printBatchResults(w io.Writer, results []BatchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"INPUT\tSTATUS\tSOURCE\tJOB\tOUTPUT")
+
+	failed, skipped := 0, 0
+	for _, r := range results {
+		status := string(chunkify.JobStatusCompleted)
+		details := r.Output
+		if r.Detached {
+			status, details = "submitted", ""
+		}
+		if r.Skipped {
+			skipped++
+			status, details = string(chunkify.JobStatusCancelled), r.Err.Error()
+		} else if r.Err != nil {
+			failed++
+			status = string(chunkify.JobStatusFailed)
+			// keep the table readable with multiline errors
+			details = strings.Join(strings.Fields(r.Err.Error()), " ")
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", indent, r.Input, status, formatter.ValueOrDash(r.SourceID), formatter.ValueOrDash(r.JobID), formatter.ValueOrDash(details))
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%s%d succeeded, %d failed", indent, len(results)-failed-skipped, failed)
+	if skipped > 0 {
+		fmt.Fprintf(w, ", %d cancelled", skipped)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,252 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/chunkify-go/option"
+)
+
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.mp4", "b.MOV", "notes.txt", ".hidden.mp4", "sub/c.mkv", "sub/d.webm"} {
+		p := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listFile := filepath.Join(dir, "inputs.txt")
+	list := "# inputs\n\nsrc_123\nhttps://example.com/video.mp4\n" + filepath.Join(dir, "a.mp4") + "\n"
+	if err := os.WriteFile(listFile, []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		listFile  string
+		recursive bool
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "directory",
+			args: []string{dir},
+			want: []string{filepath.Join(dir, "a.mp4"), filepath.Join(dir, "b.MOV")},
+		},
+		{
+			name:      "recursive directory",
+			args:      []string{dir},
+			recursive: true,
+			want:      []string{filepath.Join(dir, "a.mp4"), filepath.Join(dir, "b.MOV"), filepath.Join(dir, "sub/c.mkv"), filepath.Join(dir, "sub/d.webm")},
+		},
+		{
+			name: "glob",
+			args: []string{filepath.Join(dir, "sub", "*")},
+			want: []string{filepath.Join(dir, "sub/c.mkv"), filepath.Join(dir, "sub/d.webm")},
+		},
+		{
+			name:     "list file with duplicates",
+			args:     []string{filepath.Join(dir, "a.mp4")},
+			listFile: listFile,
+			want:     []string{filepath.Join(dir, "a.mp4"), "src_123", "https://example.com/video.mp4"},
+		},
+		{
+			name:    "missing file",
+			args:    []string{filepath.Join(dir, "missing.mp4")},
+			wantErr: true,
+		},
+		{
+			name:    "glob without match",
+			args:    []string{filepath.Join(dir, "*.avi")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandInputs(tt.args, tt.listFile, tt.recursive)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandInputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchOutputs(t *testing.T) {
+	inputs := []string{"raw/a.mov", "other/a.mp4", "https://example.com/path/b.mp4?sig=1", "src_123"}
+
+	tests := []struct {
+		name     string
+		format   string
+		outDir   string
+		template string
+		want     []string
+	}{
+		{
+			name:   "mp4",
+			format: FormatMp4H264,
+			outDir: "out",
+			want:   []string{"out/a.mp4", "out/a_2.mp4", "out/b.mp4", "out/src_123.mp4"},
+		},
+		{
+			name:   "hls",
+			format: FormatHlsH264,
+			outDir: "out",
+			want:   []string{"out/a/a.m3u8", "out/a_2/a_2.m3u8", "out/b/b.m3u8", "out/src_123/src_123.m3u8"},
+		},
+		{
+			name:     "template",
+			format:   FormatMp4H264,
+			outDir:   "out",
+			template: "{date}/{input_name}_{height}p.{ext}",
+			want:     []string{"out/{date}/a_{height}p.{ext}", "out/{date}/a_2_{height}p.{ext}", "out/{date}/b_{height}p.{ext}", "out/{date}/src_123_{height}p.{ext}"},
+		},
+		{
+			name:   "no output",
+			format: FormatWebmVp9,
+			outDir: "",
+			want:   []string{"", "", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := batchOutputs(inputs, tt.outDir, tt.template, tt.format)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batchOutputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBatchOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: ""},
+		{output: "{input_name}.{ext}"},
+		{output: "{date}/{job_id}.mp4"},
+		{output: "{date}/video.mp4", wantErr: true},
+		{output: "{input_name}_{unknown}.mp4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if err := validateBatchOutput(tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("validateBatchOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPipelineApp(t *testing.T) {
+	template := NewApp()
+	template.Command = &ChunkifyCommand{
+		Id:     "template",
+		Format: FormatHlsH264,
+		Renditions: []Rendition{
+			{Name: "720p", Height: 720},
+			{Name: "480p", Height: 480},
+		},
+	}
+
+	app := newPipelineApp(template, "a.mp4", "out/a/a.m3u8")
+
+	if app.Command.Id == template.Command.Id {
+		t.Errorf("expected a new execution id")
+	}
+	if app.Command.Input != "a.mp4" || app.Command.Output != "out/a/a.m3u8" {
+		t.Errorf("unexpected input/output: %s %s", app.Command.Input, app.Command.Output)
+	}
+	if app.Command.Renditions[0].Output != "out/a/a_720p.m3u8" || app.Command.Renditions[1].Output != "out/a/a_480p.m3u8" {
+		t.Errorf("unexpected rendition outputs: %+v", app.Command.Renditions)
+	}
+	if template.Command.Renditions[0].Output != "" {
+		t.Errorf("template renditions must not be modified")
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/sources/")
+		if id == "src_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"source not found"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"id":%q}}`, id)
+	}))
+	defer server.Close()
+
+	client := chunkify.NewClient(option.WithProjectAccessToken("sk_test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+
+	template := NewApp()
+	template.Client = &client
+	template.Command = &ChunkifyCommand{}
+
+	batch := &BatchCommand{Concurrency: 2}
+	results := batch.Run(context.Background(), template, []string{"src_1", "src_missing", "src_2"})
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, id := range []string{"src_1", "", "src_2"} {
+		if results[i].SourceID != id {
+			t.Errorf("result %d: expected source %q, got %q", i, id, results[i].SourceID)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("unexpected errors: %v, %v", results[0].Err, results[2].Err)
+	}
+	if results[1].Err == nil {
+		t.Errorf("expected an error for the missing source")
+	}
+
+	var out bytes.Buffer
+	printBatchResults(&out, results)
+	if !strings.Contains(out.String(), "2 succeeded, 1 failed") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
+
+func TestBatchRun_Interrupted(t *testing.T) {
+	template := NewApp()
+	template.Command = &ChunkifyCommand{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	batch := &BatchCommand{Concurrency: 1}
+	results := batch.Run(ctx, template, []string{"src_1", "src_2"})
+
+	for i, r := range results {
+		if !r.Skipped || r.Err == nil {
+			t.Errorf("result %d: expected the input to be skipped, got %+v", i, r)
+		}
+	}
+
+	var out bytes.Buffer
+	printBatchResults(&out, results)
+	if !strings.Contains(out.String(), "0 succeeded, 0 failed, 2 cancelled") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+}
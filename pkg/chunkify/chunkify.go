@@ -9,7 +9,9 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	_ "embed"
@@ -30,9 +32,16 @@ type ChunkifyCommand struct {
 	Input                  string
 	Output                 string
 	Format                 string
+	Profile                string
+	DownloadConcurrency    int
 	JobFormatParams        chunkify.JobNewParamsFormatUnion
 	JobTranscoderParams    chunkify.JobNewParamsTranscoder
 	JobCreateStorageParams chunkify.JobNewParamsStorage
+	Renditions             []Rendition
+	Metadata               map[string]string // Additional job metadata
+	Detach                 bool              // Exit once the job is created, without waiting for it
+	OnInterrupt            string            // What to do with the running jobs on interrupt: cancel, detach or ask when empty
+	NoDownload             bool              // Print the storage location of the outputs instead of downloading them
 }
 
 // Command represents the root notifications command and configuration
@@ -66,23 +75,22 @@ chunkify -i video.mp4
 Generate thumbnails
 chunkify -i video.mp4 -o thumbnails.jpg -f jpg -s 320x0 --interval 10
 
+Name the output after the input and the output height
+chunkify -i video.mp4 -o "out/{input_name}_{height}p.{ext}" -f mp4_h264 -s 1280x720
+
+Make an HLS ladder with 3 renditions
+chunkify -i video.mp4 -o hls/video.m3u8 -f hls_h264 --ladder 1080p:5M,720p:2.5M,480p:1M
+
 Use specific profile to use a different project
 chunkify config token sk_project_token --profile your_profile
 chunkify -i video.mp4 -f mp4/av1 --preset 7 -o video_1080p.mp4 --profile your_profile
 `,
 			Run: func(cmd *cobra.Command, args []string) {
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-
-				app.Ctx = ctx
-				app.CancelFunc = cancel
-				app.Client = cfg.Client
-
-				// Start all background work in a goroutine
-				go app.executeWorkflow(app.Ctx)
-
-				// Run TUI synchronously - this will block until the TUI exits
-				app.Run()
+				if app.Command.Detach {
+					runDetached(app, cfg)
+					return
+				}
+				runApp(app, cfg, app.executeWorkflow)
 			},
 		},
 	}
@@ -91,6 +99,26 @@ chunkify -i video.mp4 -f mp4/av1 --preset 7 -o video_1080p.mp4 --profile your_pr
 	return cmd
 }
 
+// runApp runs the workflow along with the TUI and exits with a non-zero code if it failed
+func runApp(app *App, cfg *config.Config, workflow func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.Ctx = ctx
+	app.CancelFunc = cancel
+	app.Client = cfg.Client
+	app.Command.Profile = cfg.Profile
+
+	// Start all background work in a goroutine
+	go workflow(app.Ctx)
+
+	// Run TUI synchronously - this will block until the TUI exits
+	// the error is already displayed by the TUI, we just need to exit with a non-zero code
+	if err := app.Run(); err != nil {
+		os.Exit(1)
+	}
+}
+
 func init() {
 	logFile, err := os.Create("chunkify.log")
 	if err != nil {
@@ -108,6 +136,7 @@ func (app *App) executeWorkflow(ctx context.Context) {
 		app.setError(err)
 		return
 	}
+	app.Source = source
 	app.Progress.Source <- source
 
 	// No format specified, we are done
@@ -117,6 +146,13 @@ func (app *App) executeWorkflow(ctx context.Context) {
 
 		return
 	}
+
+	// One job per rendition sharing the same HLS manifest
+	if len(app.Command.Renditions) > 0 {
+		app.executeLadder(ctx, source)
+		return
+	}
+
 	// Create job
 	app.Job, err = app.CreateJob(ctx, source)
 	if err != nil {
@@ -124,12 +160,40 @@ func (app *App) executeWorkflow(ctx context.Context) {
 		return
 	}
 
+	// Let the TUI know the job to cancel on interrupt before the first progress update
+	app.Progress.JobProgress <- *app.Job
+
+	// Expand the output placeholders now that the source and the job are known
+	if err := app.resolveOutput(source); err != nil {
+		app.setError(err)
+		return
+	}
+
+	// The job keeps running on Chunkify
+	if app.Command.Detach {
+		app.Progress.Status <- Completed
+		return
+	}
+
+	app.completeJob(ctx)
+}
+
+// completeJob follows the progress of app.Job until it ends,
+// then downloads its files if an output is specified
+func (app *App) completeJob(ctx context.Context) {
 	// Start job progress monitoring
-	go app.StartJobProgress(ctx, app.Job.ID)
+	progressErr := make(chan error, 1)
+	go func() {
+		progressErr <- app.StartJobProgress(ctx, app.Job.ID)
+	}()
 
 	// Wait for job completion
 	select {
-	case <-app.Progress.JobCompleted:
+	case err := <-progressErr:
+		if err != nil {
+			app.setError(err)
+			return
+		}
 	case <-ctx.Done():
 		return
 	}
@@ -148,28 +212,20 @@ func (app *App) executeWorkflow(ctx context.Context) {
 	default:
 	}
 
-	// Download files if output is specified
-	if app.Command.Output != "" {
+	// Download files if output is specified, or only list them with --no-download
+	switch {
+	case app.Command.NoDownload:
 		files, err := app.Client.Jobs.Files.List(ctx, app.Job.ID)
 		if err != nil {
 			app.setError(err)
 			return
 		}
 		app.Progress.Files <- files.Data
-		downloadedFiles, err := downloadFiles(ctx, app, files.Data)
-		if err != nil {
+	case app.Command.Output != "":
+		if err := app.downloadJobFiles(ctx); err != nil {
 			app.setError(err)
 			return
 		}
-
-		// Post process files if format is jpg or hls
-		// this is to rename the paths inside m3u8 and vtt files to the correct name
-		if app.Command.Format == FormatJpg || strings.HasPrefix(app.Command.Format, "hls") {
-			if err := hooks.Process(app.Command.Format, app.Job.ID, files.Data, downloadedFiles); err != nil {
-				app.setError(err)
-				return
-			}
-		}
 	}
 
 	// Mark as completed
@@ -178,29 +234,113 @@ func (app *App) executeWorkflow(ctx context.Context) {
 	app.Progress.Status <- Completed
 }
 
+// downloadJobFiles downloads the files of app.Job to the output
+func (app *App) downloadJobFiles(ctx context.Context) error {
+	if err := os.MkdirAll(path.Dir(app.Command.Output), 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	files, err := app.Client.Jobs.Files.List(ctx, app.Job.ID)
+	if err != nil {
+		return err
+	}
+	app.Progress.Files <- files.Data
+	downloadedFiles, err := downloadFiles(ctx, app, files.Data)
+	if err != nil {
+		return err
+	}
+
+	// Post process files if format is jpg or hls
+	// this is to rename the paths inside m3u8 and vtt files to the correct name
+	if app.Command.Format == FormatJpg || strings.HasPrefix(app.Command.Format, "hls") {
+		if err := hooks.Process(app.Command.Format, app.Job.ID, files.Data, downloadedFiles); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// downloadFiles downloads the files with a pool of --download-concurrency workers.
+// The progress of each file is sent to the TUI along with the overall progress
 func downloadFiles(ctx context.Context, app *App, files []chunkify.APIFile) ([]string, error) {
 	app.Progress.Status <- Downloading
 
-	slog.Info("Downloading files", "files", files)
-	downloadedFiles := []string{}
+	slog.Info("Downloading files", "files", files, "concurrency", app.Command.DownloadConcurrency)
+
+	concurrency := app.Command.DownloadConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
 
-	for _, file := range files {
+	// Aggregate the progress of all workers before sending it to the TUI
+	fileProgress := make(chan DownloadProgress, 100)
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		tracker := newDownloadTracker(files, time.Now())
+		for pr := range fileProgress {
+			app.Progress.DownloadProgress <- tracker.update(pr, time.Now())
+		}
+	}()
+
+	// Keep the order of the files for the hooks
+	downloaded := make([]string, len(files))
+	errs := make([]error, len(files))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				filepath := app.Command.OutputFor(files[i])
+				if err := DownloadFileWithRetry(ctx, files[i], filepath, fileProgress); err != nil {
+					slog.Error("Download failed", "file", files[i].Path, "output", filepath, "error", err)
+					errs[i] = err
+					continue
+				}
+				app.Progress.DownloadedFiles <- files[i]
+				downloaded[i] = filepath
+			}
+		}()
+	}
+
+	cancelled := false
+	for i := range files {
 		// Check if context was cancelled before each download
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("download cancelled")
-		default:
+			cancelled = true
+		case queue <- i:
 		}
+		if cancelled {
+			break
+		}
+	}
 
-		filepath := filename(file, app.Command.Output)
+	close(queue)
+	wg.Wait()
+	close(fileProgress)
+	<-progressDone
 
-		if err := DownloadFile(ctx, file, filepath, app.Progress.DownloadProgress); err == nil {
-			app.Progress.DownloadedFiles <- file
-			downloadedFiles = append(downloadedFiles, filepath)
+	if cancelled {
+		return nil, fmt.Errorf("download cancelled")
+	}
+
+	// Report all the files that failed after retries
+	failedFiles := []string{}
+	for i, err := range errs {
+		if err != nil {
+			failedFiles = append(failedFiles, fmt.Sprintf("%s: %s", app.Command.OutputFor(files[i]), err))
 		}
 	}
+	if len(failedFiles) > 0 {
+		return nil, fmt.Errorf("failed to download %d of %d files:\n%s%s", len(failedFiles), len(files), dblIndent, strings.Join(failedFiles, "\n"+dblIndent))
+	}
 
-	return downloadedFiles, nil
+	return downloaded, nil
 }
 
 func jobHasFailed(status string) bool {
@@ -218,7 +358,7 @@ func (a *App) CreateSource(ctx context.Context) (*chunkify.Source, error) {
 	// check input if it's a valid file or URL
 	if strings.HasPrefix(a.Command.Input, "https://") || strings.HasPrefix(a.Command.Input, "http://") {
 		// create source directly from URL
-		source, err := a.CreateSourceFromUrl()
+		source, err := a.CreateSourceFromUrl(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -248,9 +388,9 @@ func (a *App) CreateSource(ctx context.Context) (*chunkify.Source, error) {
 	return source, nil
 }
 
-func (a *App) CreateSourceFromUrl() (*chunkify.Source, error) {
+func (a *App) CreateSourceFromUrl(ctx context.Context) (*chunkify.Source, error) {
 	a.Progress.Status <- UploadingFromUrl
-	source, err := a.Client.Sources.New(context.Background(), chunkify.SourceNewParams{
+	source, err := a.Client.Sources.New(ctx, chunkify.SourceNewParams{
 		URL: a.Command.Input,
 		Metadata: map[string]string{
 			"origin":           MetadataOrigin,
@@ -282,32 +422,95 @@ func (a *App) CreateSourceFromFile(ctx context.Context) (*chunkify.Source, error
 		return source, nil
 	}
 
-	upload, err := a.Client.Uploads.New(ctx, chunkify.UploadNewParams{
-		Metadata: map[string]string{
-			"origin":           MetadataOrigin,
-			"cli_execution_id": a.Command.Id,
-			"md5":              md5,
-		},
-	})
+	// Pick up the upload of a previous run that was interrupted
+	upload, executionId, err := a.resumeUpload(ctx, md5)
 	if err != nil {
-		return nil, fmt.Errorf("error creating upload: %s", err)
+		return nil, err
+	}
+
+	// The previous upload completed, no need to upload again
+	if upload != nil && upload.Status == chunkify.UploadStatusCompleted {
+		var source *chunkify.Source
+		if upload.SourceID != "" {
+			source, err = a.Client.Sources.Get(ctx, upload.SourceID)
+		} else {
+			// the source of the upload isn't created yet
+			source, err = a.waitForSource(ctx, executionId)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting the source of upload %s: %s", upload.ID, err)
+		}
+		DeleteUploadState(md5)
+		return source, nil
 	}
 
-	// Reset file pointer to the beginning
+	if upload == nil {
+		executionId = a.Command.Id
+		upload, err = a.Client.Uploads.New(ctx, chunkify.UploadNewParams{
+			Metadata: map[string]string{
+				"origin":           MetadataOrigin,
+				"cli_execution_id": executionId,
+				"md5":              md5,
+				"file_name":        filepath.Base(a.Command.Input),
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating upload: %s", err)
+		}
+
+		if err := SaveUploadState(UploadState{Md5: md5, UploadID: upload.ID, CliExecutionID: executionId, ExpiresAt: upload.ExpiresAt}); err != nil {
+			slog.Error("Cannot save upload state", "error", err)
+		}
+	}
+
+	// Reset file pointer to the beginning. The API has no multipart upload,
+	// a pending upload is sent again from the start
 	file.Seek(0, io.SeekStart)
 
 	if err := UploadBlobWithProgress(ctx, file, upload, a.Progress.UploadProgress); err != nil {
 		return nil, fmt.Errorf("error uploading blob: %s", err)
 	}
 
-	found := false
+	source, err := a.waitForSource(ctx, executionId)
+	if err != nil {
+		return nil, err
+	}
 
+	DeleteUploadState(md5)
+	return source, nil
+}
+
+// resumeUpload returns the upload saved for the file with the given MD5 if it can still be used,
+// along with the CLI execution ID the upload was created with
+func (a *App) resumeUpload(ctx context.Context, md5 string) (*chunkify.Upload, string, error) {
+	state, err := LoadUploadState(md5)
+	if err != nil {
+		slog.Error("Cannot load upload state", "error", err)
+		return nil, "", nil
+	}
+	if state == nil {
+		return nil, "", nil
+	}
+
+	upload, err := a.Client.Uploads.Get(ctx, state.UploadID)
+	if err != nil || (upload.Status != chunkify.UploadStatusWaiting && upload.Status != chunkify.UploadStatusCompleted) {
+		slog.Info("Saved upload can't be resumed", "upload", state.UploadID, "error", err)
+		DeleteUploadState(md5)
+		return nil, "", nil
+	}
+
+	slog.Info("Resuming upload", "upload", upload.ID, "status", upload.Status)
+	return upload, state.CliExecutionID, nil
+}
+
+// waitForSource waits for the source created from the upload of the given CLI execution
+func (a *App) waitForSource(ctx context.Context, executionId string) (*chunkify.Source, error) {
 	retry := 0
 	maxRetries := 30
-	for !found && retry < maxRetries {
+	for retry < maxRetries {
 		results, err := a.Client.Sources.List(ctx, chunkify.SourceListParams{
 			Metadata: [][]string{
-				{"cli_execution_id", a.Command.Id},
+				{"cli_execution_id", executionId},
 			},
 		})
 		if err != nil {
@@ -315,8 +518,7 @@ func (a *App) CreateSourceFromFile(ctx context.Context) (*chunkify.Source, error
 		}
 		for _, source := range results.Data {
 			if source.Metadata != nil {
-				if v, ok := source.Metadata["cli_execution_id"]; ok && v == a.Command.Id {
-					found = true
+				if v, ok := source.Metadata["cli_execution_id"]; ok && v == executionId {
 					return &source, nil
 				}
 			}
@@ -359,10 +561,7 @@ func (a *App) CreateJob(ctx context.Context, source *chunkify.Source) (*chunkify
 		Transcoder:    a.Command.JobTranscoderParams,
 		Storage:       a.Command.JobCreateStorageParams,
 		HlsManifestID: chunkify.String(*hlsManifestId),
-		Metadata: map[string]string{
-			"origin":           MetadataOrigin,
-			"cli_execution_id": a.Command.Id,
-		},
+		Metadata:      a.Command.JobMetadata(nil),
 	})
 
 	if err != nil {
@@ -371,37 +570,50 @@ func (a *App) CreateJob(ctx context.Context, source *chunkify.Source) (*chunkify
 	return job, nil
 }
 
-func (a *App) StartJobProgress(ctx context.Context, jobId string) {
+func (a *App) StartJobProgress(ctx context.Context, jobId string) error {
 	ticker := time.NewTicker(ProgressUpdateInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			a.Progress.JobCompleted <- true
-			return
+			return ctx.Err()
 		case <-ticker.C:
 			job, err := a.Client.Jobs.Get(ctx, jobId)
 			if err != nil {
-				return
+				return fmt.Errorf("error getting job %s: %w", jobId, err)
 			}
 			a.Job = job
 			a.Progress.JobProgress <- *job
 
 			if job.Status == chunkify.JobStatusCompleted || jobHasFailed(string(job.Status)) {
-				a.Progress.JobCompleted <- true
-				break
+				return nil
 			}
 
 			transcoders, err := a.Client.Jobs.Transcoders.List(ctx, job.ID)
 			if err != nil {
-				return
+				return fmt.Errorf("error listing the transcoders of job %s: %w", job.ID, err)
 			}
 			a.Progress.JobTranscoders <- transcoders.Data
 		}
 	}
 }
 
+// JobMetadata returns the metadata of the jobs created by the command.
+// The origin and the execution ID can't be overridden as they are used to find the jobs
+func (c *ChunkifyCommand) JobMetadata(extra map[string]string) map[string]string {
+	metadata := map[string]string{}
+	for k, v := range c.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range extra {
+		metadata[k] = v
+	}
+	metadata["origin"] = MetadataOrigin
+	metadata["cli_execution_id"] = c.Id
+	return metadata
+}
+
 func filename(file chunkify.APIFile, output string) string {
 	fileBase := strings.Replace(path.Base(output), path.Ext(output), "", 1)
 	newFilename := strings.Replace(path.Base(file.Path), file.JobID, fileBase, 1)
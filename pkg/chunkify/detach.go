@@ -0,0 +1,97 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+)
+
+// DetachedJob is the output of a run with --detach
+type DetachedJob struct {
+	SourceID      string   `json:"source_id"`
+	JobID         string   `json:"job_id,omitempty"`
+	JobIDs        []string `json:"job_ids,omitempty"` // the jobs of all the renditions of a ladder
+	HlsManifestID string   `json:"hls_manifest_id,omitempty"`
+	Output        string   `json:"output,omitempty"`
+}
+
+// runDetached creates the source and the job without the TUI, prints their IDs
+// and exits without waiting for the job
+func runDetached(app *App, cfg *config.Config) {
+	app.Client = cfg.Client
+	app.Command.Profile = cfg.Profile
+
+	if !app.JSON {
+		fmt.Printf("%sSubmitting %s\n\n", indent, app.Command.Input)
+	}
+
+	final := app.runPipeline(context.Background())
+	if final.Error != nil {
+		fmt.Printf("Error: %s\n", final.Error)
+		os.Exit(1)
+	}
+
+	if err := printDetachedJob(os.Stdout, app.detachedJob(), app.JSON); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// detachedJob returns the IDs of the source and the jobs created by the workflow
+func (app *App) detachedJob() DetachedJob {
+	detached := DetachedJob{Output: app.Command.Output}
+	if app.Source != nil {
+		detached.SourceID = app.Source.ID
+	}
+	if app.Job != nil {
+		detached.JobID = app.Job.ID
+		detached.HlsManifestID = app.Job.HlsManifestID
+	}
+	if len(app.Jobs) > 1 {
+		for _, job := range app.Jobs {
+			detached.JobIDs = append(detached.JobIDs, job.ID)
+		}
+	}
+	return detached
+}
+
+func printDetachedJob(w io.Writer, detached DetachedJob, jsonOutput bool) error {
+	if jsonOutput {
+		return formatter.PrintJSON(w, detached)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%sSource ID:\t%s\n", indent, detached.SourceID)
+	if len(detached.JobIDs) > 0 {
+		fmt.Fprintf(tw, "%sJob IDs:\t%s\n", indent, strings.Join(detached.JobIDs, ", "))
+	} else if detached.JobID != "" {
+		fmt.Fprintf(tw, "%sJob ID:\t%s\n", indent, detached.JobID)
+	}
+	if detached.HlsManifestID != "" {
+		fmt.Fprintf(tw, "%sHLS manifest ID:\t%s\n", indent, detached.HlsManifestID)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// jobs watch follows a single job, it can't follow the renditions of a ladder
+	if detached.JobID != "" && len(detached.JobIDs) == 0 {
+		fmt.Fprintf(w, "\n%s%s\n", indent, watchHint(detached.JobID, detached.Output))
+	}
+	return nil
+}
+
+// watchHint tells how to follow a job left running on Chunkify
+func watchHint(jobID, output string) string {
+	watch := "chunkify jobs watch " + jobID
+	if output != "" {
+		watch += " -o " + output
+	}
+	return fmt.Sprintf("Follow the job with `%s`", watch)
+}
@@ -0,0 +1,66 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExecuteWorkflow_Detach(t *testing.T) {
+	newGlobalFlags()
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/sources/src_1":
+			fmt.Fprint(w, `{"data":{"id":"src_1","width":1920,"height":1080}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/jobs":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"source_id":"src_1"`) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"id":"job_1","status":"queued","source_id":"src_1","hls_manifest_id":"hls_1","format":{"id":"hls_h264"}}}`)
+		default:
+			// the job must not be followed
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	app := NewApp()
+	app.Client = client
+	app.Command = &ChunkifyCommand{Input: "src_1", Output: "hls/{job_id}.m3u8", Format: FormatHlsH264, Detach: true}
+
+	final := app.runPipeline(context.Background())
+	if final.Error != nil {
+		t.Fatal(final.Error)
+	}
+
+	detached := app.detachedJob()
+	if detached.SourceID != "src_1" || detached.JobID != "job_1" || detached.HlsManifestID != "hls_1" || detached.Output != "hls/job_1.m3u8" {
+		t.Errorf("unexpected detached job: %+v", detached)
+	}
+}
+
+func TestPrintDetachedJob(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printDetachedJob(&buf, DetachedJob{SourceID: "src_1", JobID: "job_1", Output: "video.mp4"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "job_1") || !strings.Contains(buf.String(), "chunkify jobs watch job_1 -o video.mp4") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := printDetachedJob(&buf, DetachedJob{SourceID: "src_1", JobID: "job_1", JobIDs: []string{"job_1", "job_2"}, HlsManifestID: "hls_1"}, true); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"source_id":"src_1","job_id":"job_1","job_ids":["job_1","job_2"],"hls_manifest_id":"hls_1"}`
+	if strings.TrimSpace(buf.String()) != want {
+		t.Errorf("expected %s, got %s", want, buf.String())
+	}
+}
@@ -2,11 +2,14 @@ package chunkify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	chunkify "github.com/chunkifydev/chunkify-go"
@@ -14,6 +17,19 @@ import (
 
 type DownloadProgress struct {
 	File         chunkify.APIFile
+	Progress     float64
+	TotalBytes   int64
+	WrittenBytes int64
+	ResumedBytes int64 // bytes already on disk when the download was resumed
+	Eta          time.Duration
+	Speed        float64 // bytes/sec
+
+	// Overall is the progress of all the files being downloaded concurrently
+	Overall OverallDownloadProgress
+}
+
+// OverallDownloadProgress aggregates the progress of several file downloads
+type OverallDownloadProgress struct {
 	Progress     float64
 	TotalBytes   int64
 	WrittenBytes int64
@@ -25,6 +41,7 @@ type progressWriter struct {
 	w            io.Writer
 	total        int64 // content-length (may be 0 when unknown)
 	written      int64
+	resumed      int64 // bytes already on disk when the download was resumed
 	start        time.Time
 	lastUpdate   time.Time
 	updateEvery  time.Duration
@@ -50,27 +67,152 @@ func (pw *progressWriter) print(now time.Time) {
 	if elapsed <= 0 {
 		elapsed = 0.001
 	}
-	speed := float64(pw.written) / elapsed // bytes/sec
+	speed := float64(pw.written-pw.resumed) / elapsed // bytes/sec
 
 	pr := DownloadProgress{
 		File:         pw.file,
 		TotalBytes:   pw.total,
 		WrittenBytes: pw.written,
+		ResumedBytes: pw.resumed,
 		Speed:        speed,
 	}
 
 	if pw.total > 0 {
-		remain := pw.total - pw.written
-		eta := time.Duration(float64(remain)/speed) * time.Second
 		percent := float64(pw.written) * 100 / float64(pw.total)
 		pr.Progress = percent
-		pr.Eta = eta.Truncate(time.Second)
+		if speed > 0 {
+			remain := pw.total - pw.written
+			eta := time.Duration(float64(remain)/speed) * time.Second
+			pr.Eta = eta.Truncate(time.Second)
+		}
 	}
 
 	pw.progressChan <- pr
 }
 
+// downloadTracker aggregates the progress of files downloaded concurrently
+type downloadTracker struct {
+	start   time.Time
+	sizes   map[string]int64 // expected size of each file
+	written map[string]int64
+	resumed map[string]int64
+}
+
+func newDownloadTracker(files []chunkify.APIFile, start time.Time) *downloadTracker {
+	d := &downloadTracker{
+		start:   start,
+		sizes:   map[string]int64{},
+		written: map[string]int64{},
+		resumed: map[string]int64{},
+	}
+	for _, file := range files {
+		d.sizes[file.ID] = file.Size
+	}
+	return d
+}
+
+// update records the progress of one file and returns it with the overall progress
+func (d *downloadTracker) update(pr DownloadProgress, now time.Time) DownloadProgress {
+	d.written[pr.File.ID] = pr.WrittenBytes
+	d.resumed[pr.File.ID] = pr.ResumedBytes
+	if pr.TotalBytes > d.sizes[pr.File.ID] {
+		d.sizes[pr.File.ID] = pr.TotalBytes
+	}
+
+	var total, written, resumed int64
+	for id, size := range d.sizes {
+		total += size
+		written += d.written[id]
+		resumed += d.resumed[id]
+	}
+
+	elapsed := now.Sub(d.start).Seconds()
+	if elapsed <= 0 {
+		elapsed = 0.001
+	}
+	speed := float64(written-resumed) / elapsed // bytes/sec
+
+	overall := OverallDownloadProgress{
+		TotalBytes:   total,
+		WrittenBytes: written,
+		Speed:        speed,
+	}
+
+	if total > 0 {
+		overall.Progress = float64(written) * 100 / float64(total)
+		if speed > 0 {
+			eta := time.Duration(float64(total-written)/speed) * time.Second
+			overall.Eta = eta.Truncate(time.Second)
+		}
+	}
+
+	pr.Overall = overall
+	return pr
+}
+
+// DownloadRetries is the number of times a failed download is retried
+// DownloadRetryBackoff is the delay before the first retry, doubled after each attempt
+var (
+	DownloadRetries      = 3
+	DownloadRetryBackoff = 1 * time.Second
+)
+
+// downloadStatusError is a download answered with an unexpected status code
+type downloadStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *downloadStatusError) Error() string {
+	return fmt.Sprintf("bad status: %s", e.Status)
+}
+
+// isPermanentDownloadError reports whether retrying the download can't help
+func isPermanentDownloadError(err error) bool {
+	var statusErr *downloadStatusError
+	return errors.As(err, &statusErr) && isPermanentStatus(statusErr.StatusCode)
+}
+
+// isPermanentStatus reports whether a request answered with this status code can't succeed when retried:
+// the client errors, e.g. an expired URL, except 408 Request Timeout and 429 Too Many Requests
+func isPermanentStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+// DownloadFileWithRetry calls DownloadFile until it succeeds, with exponential backoff between attempts.
+// Each new attempt resumes from the `.part` file left by the previous one.
+// Client errors other than 408 and 429 are not retried.
+func DownloadFileWithRetry(ctx context.Context, file chunkify.APIFile, output string, progressChan chan DownloadProgress) error {
+	backoff := DownloadRetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= DownloadRetries; attempt++ {
+		if attempt > 0 {
+			slog.Info("Retrying download", "file", file.Path, "attempt", attempt, "backoff", backoff, "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err = DownloadFile(ctx, file, output, progressChan); err == nil {
+			return nil
+		}
+
+		// no need to retry if the download was cancelled or refused
+		if ctx.Err() != nil || isPermanentDownloadError(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
 // DownloadFile streams a URL to `output` with console progress.
+// If a `.part` file was left by a previous attempt, only the remaining bytes are requested
+// when the server supports ranges and the file didn't change, otherwise the download restarts from zero.
 func DownloadFile(ctx context.Context, file chunkify.APIFile, output string, progressChan chan DownloadProgress) error {
 	slog.Info("Downloading file", "file", file.Path, "output", output)
 
@@ -87,40 +229,85 @@ func DownloadFile(ctx context.Context, file chunkify.APIFile, output string, pro
 		},
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, file.URL, nil)
-	if err != nil {
-		return fmt.Errorf("build request: %w", err)
+	// Prepare output file (atomic write via temp file)
+	tmp := output + ".part"
+
+	// Resume from the partial file if any
+	var offset int64
+	if info, err := os.Stat(tmp); err == nil && info.Mode().IsRegular() {
+		offset = info.Size()
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request: %w", err)
+	// the previous attempt got all the bytes but stopped before moving the file into place
+	if offset > 0 && offset == file.Size {
+		slog.Info("Partial download already complete", "file", file.Path, "output", output)
+		pw := &progressWriter{total: offset, written: offset, resumed: offset, start: time.Now(), progressChan: progressChan, file: file}
+		pw.print(time.Now())
+		return completeDownload(tmp, output)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+	// the partial file is only resumed if the file didn't change since it was started
+	var etag string
+	if offset > 0 {
+		etag = readPartETag(tmp)
 	}
 
-	// Prepare output file (atomic write via temp file)
-	tmp := output + ".part"
-	out, err := os.Create(tmp)
+	resp, err := requestFile(ctx, client, file.URL, offset, etag)
 	if err != nil {
-		return fmt.Errorf("create file: %w", err)
+		return err
 	}
 	defer func() {
-		out.Close()
-		// best effort: if we exit with error, leave the .part file for troubleshooting/resume logic
+		resp.Body.Close()
 	}()
 
+	if offset > 0 && !canResume(resp, offset) {
+		// ranges are not supported or the partial file doesn't match, restart from zero
+		slog.Info("Cannot resume download, restarting", "file", file.Path, "status", resp.Status, "offset", offset)
+		resp.Body.Close()
+
+		offset = 0
+		resp, err = requestFile(ctx, client, file.URL, offset, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	if offset == 0 && resp.StatusCode != http.StatusOK {
+		return &downloadStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if offset == 0 {
+		savePartETag(tmp, resp.Header.Get("ETag"))
+	}
+
 	var total int64
-	if resp.ContentLength > 0 {
-		total = resp.ContentLength
+	var out *os.File
+
+	if offset > 0 {
+		slog.Info("Resuming download", "file", file.Path, "offset", offset)
+		_, _, total, _ = parseContentRange(resp.Header.Get("Content-Range"))
+		if total <= 0 && resp.ContentLength > 0 {
+			total = offset + resp.ContentLength
+		}
+		out, err = os.OpenFile(tmp, os.O_WRONLY|os.O_APPEND, 0644)
+	} else {
+		if resp.ContentLength > 0 {
+			total = resp.ContentLength
+		}
+		out, err = os.Create(tmp)
+	}
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
 	}
+	defer func() {
+		out.Close()
+		// best effort: if we exit with error, leave the .part file so the next attempt can resume it
+	}()
 
 	pw := &progressWriter{
 		w:            out,
 		total:        total,
+		written:      offset,
+		resumed:      offset,
 		start:        time.Now(),
 		updateEvery:  200 * time.Millisecond,
 		progressChan: progressChan,
@@ -140,10 +327,120 @@ func DownloadFile(ctx context.Context, file chunkify.APIFile, output string, pro
 		return fmt.Errorf("close file: %w", err)
 	}
 
-	// Atomically move into place
+	// Verify the size before moving the file into place
+	if file.Size > 0 && pw.written != file.Size {
+		if pw.written > file.Size {
+			// the partial file can't be resumed, start over next time
+			os.Remove(tmp)
+		}
+		return fmt.Errorf("size mismatch: expected %d bytes, got %d", file.Size, pw.written)
+	}
+
+	return completeDownload(tmp, output)
+}
+
+// completeDownload atomically moves the partial file into place
+func completeDownload(tmp string, output string) error {
 	if err := os.Rename(tmp, output); err != nil {
 		return fmt.Errorf("rename %q -> %q: %w", tmp, output, err)
 	}
+	os.Remove(tmp + ".etag")
 
 	return nil
 }
+
+// readPartETag returns the ETag of the file downloaded into the partial file, empty if unknown
+func readPartETag(tmp string) string {
+	etag, err := os.ReadFile(tmp + ".etag")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(etag))
+}
+
+// savePartETag keeps the ETag of the file downloaded into the partial file, to resume it with If-Range.
+// Weak ETags can't be used with If-Range
+func savePartETag(tmp string, etag string) {
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		os.Remove(tmp + ".etag")
+		return
+	}
+	if err := os.WriteFile(tmp+".etag", []byte(etag+"\n"), 0644); err != nil {
+		slog.Error("Cannot save the ETag of the download", "file", tmp, "error", err)
+	}
+}
+
+// requestFile sends a GET request for the file, starting at offset when it's greater than 0.
+// With an ETag, the server sends the whole file instead of the range if the file changed
+func requestFile(ctx context.Context, client *http.Client, url string, offset int64, etag string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if etag != "" {
+			req.Header.Set("If-Range", etag)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	return resp, nil
+}
+
+// canResume checks that the server answered the range request with the bytes starting at offset
+func canResume(resp *http.Response, offset int64) bool {
+	if resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+
+	if strings.EqualFold(resp.Header.Get("Accept-Ranges"), "none") {
+		return false
+	}
+
+	start, _, _, err := parseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return false
+	}
+
+	return start == offset
+}
+
+// parseContentRange parses a Content-Range header like "bytes 100-199/200".
+// total is -1 when the complete length is unknown ("bytes 100-199/*")
+func parseContentRange(contentRange string) (start, end, total int64, err error) {
+	unit, rangeSpec, ok := strings.Cut(strings.TrimSpace(contentRange), " ")
+	if !ok || unit != "bytes" {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", contentRange)
+	}
+
+	byteRange, totalStr, ok := strings.Cut(rangeSpec, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", contentRange)
+	}
+
+	startStr, endStr, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid content range: %q", contentRange)
+	}
+
+	if start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid content range start: %q", contentRange)
+	}
+	if end, err = strconv.ParseInt(endStr, 10, 64); err != nil || end < start {
+		return 0, 0, 0, fmt.Errorf("invalid content range end: %q", contentRange)
+	}
+
+	total = -1
+	if totalStr != "*" {
+		if total, err = strconv.ParseInt(totalStr, 10, 64); err != nil || total <= end {
+			return 0, 0, 0, fmt.Errorf("invalid content range length: %q", contentRange)
+		}
+	}
+
+	return start, end, total, nil
+}
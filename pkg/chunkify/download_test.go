@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -363,3 +364,454 @@ func TestDownloadFile_ProgressUpdates(t *testing.T) {
 		}
 	}
 }
+
+func TestDownloadFile_Resume(t *testing.T) {
+	testData := []byte("Hello, World! This is test data for a resumed download.")
+	partial := 10
+
+	var rangeHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader = r.Header.Get("Range")
+		http.ServeContent(w, r, "test.txt", time.Time{}, bytes.NewReader(testData))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+
+	// leave a partial file from a previous attempt
+	if err := os.WriteFile(outputFile+".part", testData[:partial], 0644); err != nil {
+		t.Fatalf("Failed to create part file: %v", err)
+	}
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFile(context.Background(), file, outputFile, progressChan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rangeHeader != fmt.Sprintf("bytes=%d-", partial) {
+		t.Errorf("Expected range header bytes=%d-, got %q", partial, rangeHeader)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if string(content) != string(testData) {
+		t.Errorf("Expected file content %q, got %q", testData, content)
+	}
+
+	if _, err := os.Stat(outputFile + ".part"); !os.IsNotExist(err) {
+		t.Error("Expected .part file to be removed after successful download")
+	}
+}
+
+func TestDownloadFile_ResumeUnsupported(t *testing.T) {
+	testData := []byte("Hello, World! This server doesn't support ranges.")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// ignore the Range header and send everything
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(testData)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(testData)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+
+	// a stale partial file that must not be kept
+	if err := os.WriteFile(outputFile+".part", []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to create part file: %v", err)
+	}
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFile(context.Background(), file, outputFile, progressChan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests (range then full restart), got %d", requests)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if string(content) != string(testData) {
+		t.Errorf("Expected file content %q, got %q", testData, content)
+	}
+}
+
+func TestDownloadFile_PartComplete(t *testing.T) {
+	testData := []byte("Hello, World! Every byte was already downloaded.")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+	if err := os.WriteFile(outputFile+".part", testData, 0644); err != nil {
+		t.Fatalf("Failed to create part file: %v", err)
+	}
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL, Size: int64(len(testData))}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFile(context.Background(), file, outputFile, progressChan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected no request, got %d", requests)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil || string(content) != string(testData) {
+		t.Errorf("Expected the part file to be moved into place, got %q, %v", content, err)
+	}
+}
+
+func TestDownloadFile_ResumeChangedFile(t *testing.T) {
+	testData := []byte("Hello, World! The file changed since the partial download.")
+
+	ifRanges := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		w.Header().Set("ETag", `"new"`)
+		http.ServeContent(w, r, "test.txt", time.Time{}, bytes.NewReader(testData))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+
+	// a partial file of the previous version
+	if err := os.WriteFile(outputFile+".part", []byte("old data"), 0644); err != nil {
+		t.Fatalf("Failed to create part file: %v", err)
+	}
+	if err := os.WriteFile(outputFile+".part.etag", []byte(`"old"`), 0644); err != nil {
+		t.Fatalf("Failed to create etag file: %v", err)
+	}
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFile(context.Background(), file, outputFile, progressChan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(ifRanges) == 0 || ifRanges[0] != `"old"` {
+		t.Errorf("Expected the range to be requested with If-Range, got %v", ifRanges)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil || string(content) != string(testData) {
+		t.Errorf("Expected the new file to be downloaded from the start, got %q, %v", content, err)
+	}
+	if _, err := os.Stat(outputFile + ".part.etag"); !os.IsNotExist(err) {
+		t.Error("Expected the etag file to be removed after successful download")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		start       int64
+		end         int64
+		total       int64
+		expectError bool
+	}{
+		{header: "bytes 100-199/200", start: 100, end: 199, total: 200},
+		{header: "bytes 0-0/1", start: 0, end: 0, total: 1},
+		{header: "bytes 100-199/*", start: 100, end: 199, total: -1},
+		{header: "", expectError: true},
+		{header: "items 100-199/200", expectError: true},
+		{header: "bytes 100-199", expectError: true},
+		{header: "bytes */200", expectError: true},
+		{header: "bytes 199-100/200", expectError: true},
+		{header: "bytes 100-199/150", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, end, total, err := parseContentRange(tt.header)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if start != tt.start || end != tt.end || total != tt.total {
+				t.Errorf("Expected %d-%d/%d, got %d-%d/%d", tt.start, tt.end, tt.total, start, end, total)
+			}
+		})
+	}
+}
+
+func TestDownloadTracker_Update(t *testing.T) {
+	start := time.Now()
+	files := []chunkify.APIFile{
+		{ID: "file_1", Size: 100},
+		{ID: "file_2", Size: 300},
+	}
+	tracker := newDownloadTracker(files, start)
+
+	pr := tracker.update(DownloadProgress{File: files[0], WrittenBytes: 100, TotalBytes: 100}, start.Add(time.Second))
+	if pr.Overall.TotalBytes != 400 {
+		t.Errorf("Expected overall TotalBytes 400, got %d", pr.Overall.TotalBytes)
+	}
+	if pr.Overall.WrittenBytes != 100 {
+		t.Errorf("Expected overall WrittenBytes 100, got %d", pr.Overall.WrittenBytes)
+	}
+	if pr.Overall.Progress != 25 {
+		t.Errorf("Expected overall Progress 25, got %f", pr.Overall.Progress)
+	}
+
+	pr = tracker.update(DownloadProgress{File: files[1], WrittenBytes: 100, TotalBytes: 300}, start.Add(2*time.Second))
+	if pr.Overall.WrittenBytes != 200 {
+		t.Errorf("Expected overall WrittenBytes 200, got %d", pr.Overall.WrittenBytes)
+	}
+	if pr.Overall.Speed != 100 {
+		t.Errorf("Expected overall Speed 100, got %f", pr.Overall.Speed)
+	}
+	if pr.Overall.Eta != 2*time.Second {
+		t.Errorf("Expected overall Eta 2s, got %v", pr.Overall.Eta)
+	}
+	// the per-file progress is kept as is
+	if pr.File.ID != "file_2" || pr.WrittenBytes != 100 {
+		t.Errorf("Expected file_2 progress to be kept, got %s %d", pr.File.ID, pr.WrittenBytes)
+	}
+}
+
+func TestDownloadTracker_Update_Resumed(t *testing.T) {
+	start := time.Now()
+	files := []chunkify.APIFile{{ID: "file_1", Size: 1000}}
+	tracker := newDownloadTracker(files, start)
+
+	// 800 bytes were already on disk, only 100 were downloaded in 1s
+	pr := tracker.update(DownloadProgress{File: files[0], WrittenBytes: 900, ResumedBytes: 800}, start.Add(time.Second))
+	if pr.Overall.Speed != 100 {
+		t.Errorf("Expected overall Speed 100, got %f", pr.Overall.Speed)
+	}
+	if pr.Overall.Progress != 90 {
+		t.Errorf("Expected overall Progress 90, got %f", pr.Overall.Progress)
+	}
+}
+
+func TestDownloadFiles_Concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	files := []chunkify.APIFile{}
+	for i := range 5 {
+		files = append(files, chunkify.APIFile{
+			ID:    fmt.Sprintf("file_%d", i),
+			JobID: "job_1",
+			Path:  fmt.Sprintf("/job_1-%d.jpg", i),
+			URL:   fmt.Sprintf("%s/%d", server.URL, i),
+		})
+	}
+
+	app := NewApp()
+	app.Command = &ChunkifyCommand{
+		Output:              filepath.Join(tempDir, "image.jpg"),
+		DownloadConcurrency: 3,
+	}
+
+	downloaded, err := downloadFiles(context.Background(), app, files)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(downloaded) != len(files) {
+		t.Fatalf("Expected %d downloaded files, got %d", len(files), len(downloaded))
+	}
+
+	for i, filepath := range downloaded {
+		expected := app.Command.OutputFor(files[i])
+		if filepath != expected {
+			t.Errorf("Expected downloaded files in order, got %s at %d instead of %s", filepath, i, expected)
+		}
+		content, err := os.ReadFile(filepath)
+		if err != nil {
+			t.Fatalf("Failed to read downloaded file: %v", err)
+		}
+		if string(content) != fmt.Sprintf("/%d", i) {
+			t.Errorf("Unexpected content for %s: %q", filepath, content)
+		}
+	}
+}
+
+func TestDownloadFile_SizeMismatch(t *testing.T) {
+	testData := []byte("Hello, World!")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testData)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL, Size: int64(len(testData) - 1)}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFile(context.Background(), file, outputFile, progressChan); err == nil {
+		t.Fatal("Expected size mismatch error")
+	}
+
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Error("Expected output file to not be created on size mismatch")
+	}
+
+	// the downloaded file is bigger than expected, it can't be resumed
+	if _, err := os.Stat(outputFile + ".part"); !os.IsNotExist(err) {
+		t.Error("Expected .part file to be removed when bigger than expected")
+	}
+}
+
+func TestDownloadFileWithRetry(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	defer func() { DownloadRetryBackoff = 1 * time.Second }()
+
+	testData := []byte("Hello, World! This download fails twice.")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(testData)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "downloaded.txt")
+
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL, Size: int64(len(testData))}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFileWithRetry(context.Background(), file, outputFile, progressChan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if string(content) != string(testData) {
+		t.Errorf("Expected file content %q, got %q", testData, content)
+	}
+}
+
+func TestDownloadFileWithRetry_GivesUp(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	defer func() { DownloadRetryBackoff = 1 * time.Second }()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL}
+	progressChan := make(chan DownloadProgress, 100)
+
+	if err := DownloadFileWithRetry(context.Background(), file, filepath.Join(tempDir, "downloaded.txt"), progressChan); err == nil {
+		t.Fatal("Expected error after all retries")
+	}
+
+	if requests != DownloadRetries+1 {
+		t.Errorf("Expected %d requests, got %d", DownloadRetries+1, requests)
+	}
+}
+
+func TestDownloadFileWithRetry_ClientErrors(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	defer func() { DownloadRetryBackoff = 1 * time.Second }()
+
+	tests := []struct {
+		status   int
+		requests int
+	}{
+		{status: http.StatusForbidden, requests: 1},
+		{status: http.StatusNotFound, requests: 1},
+		{status: http.StatusRequestTimeout, requests: DownloadRetries + 1},
+		{status: http.StatusTooManyRequests, requests: DownloadRetries + 1},
+	}
+
+	for _, tt := range tests {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(tt.status)
+		}))
+
+		file := chunkify.APIFile{ID: "file_test", Path: "test.txt", URL: server.URL}
+		err := DownloadFileWithRetry(context.Background(), file, filepath.Join(t.TempDir(), "downloaded.txt"), make(chan DownloadProgress, 100))
+		server.Close()
+
+		if err == nil {
+			t.Errorf("%d: expected an error", tt.status)
+		}
+		if requests != tt.requests {
+			t.Errorf("%d: expected %d requests, got %d", tt.status, tt.requests, requests)
+		}
+	}
+}
+
+func TestDownloadFiles_ReportsFailedFiles(t *testing.T) {
+	DownloadRetryBackoff = time.Millisecond
+	defer func() { DownloadRetryBackoff = 1 * time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	files := []chunkify.APIFile{
+		{ID: "file_1", JobID: "job_1", Path: "/job_1.m3u8", URL: server.URL + "/ok"},
+		{ID: "file_2", JobID: "job_1", Path: "/job_1.mp4", URL: server.URL + "/missing"},
+	}
+
+	app := NewApp()
+	app.Command = &ChunkifyCommand{
+		Output:              filepath.Join(tempDir, "video.m3u8"),
+		DownloadConcurrency: 2,
+	}
+
+	_, err := downloadFiles(context.Background(), app, files)
+	if err == nil {
+		t.Fatal("Expected error when a file fails to download")
+	}
+
+	if !strings.Contains(err.Error(), "failed to download 1 of 2 files") || !strings.Contains(err.Error(), "video.mp4") {
+		t.Errorf("Expected error to list the failed file, got %q", err.Error())
+	}
+}
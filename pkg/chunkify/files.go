@@ -0,0 +1,149 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+func NewFilesCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "files",
+			Short: "List and download the files of a job",
+			Long: `List and download the files of a job
+
+Examples:
+
+chunkify files list job_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify files download job_2G6MJiNz71bHQGNzGwKx5cJwPFS -o hls/video.m3u8
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newFilesListCommand(cfg))
+	cmd.Command.AddCommand(newFilesDownloadCommand(cfg))
+
+	return cmd
+}
+
+func newFilesListCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list <job_id>",
+		Short:        "List the files of a job",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := cfg.Client.Jobs.Files.List(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("error listing files: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, files.Data)
+			}
+			return printFiles(os.Stdout, files.Data)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newFilesDownloadCommand(cfg *config.Config) *cobra.Command {
+	app := NewApp()
+	app.Command = &ChunkifyCommand{}
+
+	cmd := &cobra.Command{
+		Use:   "download <job_id>",
+		Short: "Download the files of a completed job",
+		Long: `Download the files of a completed job
+
+The files are named after the output, and the HLS manifests and the JPG sprite VTT file are rewritten like in a normal run.
+The {input_name} placeholder of the output is replaced by the source ID.`,
+		Example: "chunkify files download job_2G6MJiNz71bHQGNzGwKx5cJwPFS -o hls/video.m3u8",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if app.Command.DownloadConcurrency < 1 || app.Command.DownloadConcurrency > 32 {
+				return fmt.Errorf("--download-concurrency must be between 1 and 32")
+			}
+			return validateOutputTemplate(app.Command.Output)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			runApp(app, cfg, func(ctx context.Context) {
+				app.executeFilesDownload(ctx, args[0])
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&app.Command.Output, "output", "o", "", "Output file path. It can contain placeholders like {job_id} or {height}")
+	cmd.Flags().IntVar(&app.Command.DownloadConcurrency, "download-concurrency", 4, "Number of files to download at the same time (1-32)")
+	cmd.Flags().BoolVar(&app.JSON, "json", false, "Output in JSON format")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+// executeFilesDownload downloads the files of a completed job
+func (app *App) executeFilesDownload(ctx context.Context, jobId string) {
+	if err := app.loadJob(ctx, jobId); err != nil {
+		app.setError(err)
+		return
+	}
+
+	if jobHasFailed(string(app.Job.Status)) {
+		app.setError(fmt.Errorf("job failed with status: %s: %s", app.Job.Status, app.Job.Error.Message))
+		return
+	}
+	if app.Job.Status != chunkify.JobStatusCompleted {
+		app.setError(fmt.Errorf("job %s is %s, use `chunkify jobs watch %s -o %s` to download its files when it completes", app.Job.ID, app.Job.Status, app.Job.ID, app.Command.Output))
+		return
+	}
+
+	if err := app.downloadJobFiles(ctx); err != nil {
+		app.setError(err)
+		return
+	}
+
+	// give enough time to display the completed message
+	time.Sleep(1 * time.Second)
+	app.Progress.Status <- Completed
+}
+
+func printFiles(w io.Writer, files []chunkify.APIFile) error {
+	if len(files) == 0 {
+		fmt.Fprintln(w, indent+"No file found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"PATH\tSIZE\tTYPE\tRESOLUTION\tDURATION")
+	for _, f := range files {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%dx%d\t%s\n",
+			indent,
+			f.Path,
+			formatter.Size(f.Size),
+			formatter.ValueOrDash(f.MimeType),
+			f.Width,
+			f.Height,
+			formatter.Duration(f.Duration))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(files))
+	return nil
+}
@@ -0,0 +1,72 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chunkifydev/chunkify-go"
+)
+
+func TestExecuteFilesDownload(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		wantErr string
+	}{
+		{name: "completed", status: "completed"},
+		{name: "running", status: "transcoding", wantErr: "job job_1 is transcoding"},
+		{name: "failed", status: "failed", wantErr: "job failed with status: failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "out", "video.mp4")
+			app := NewApp()
+			app.Client = newTestJobClient(t, tt.status)
+			app.Command = &ChunkifyCommand{Output: output, DownloadConcurrency: 2}
+
+			final := runTestWorkflow(app, func(ctx context.Context) {
+				app.executeFilesDownload(ctx, "job_1")
+			})
+
+			if tt.wantErr != "" {
+				if final.Error == nil || !strings.Contains(final.Error.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, final.Error)
+				}
+				if _, err := os.Stat(output); err == nil {
+					t.Errorf("expected no file to be downloaded")
+				}
+				return
+			}
+
+			if final.Error != nil {
+				t.Fatal(final.Error)
+			}
+			if content, err := os.ReadFile(output); err != nil || string(content) != "video" {
+				t.Errorf("expected the file to be downloaded, got %q, %v", content, err)
+			}
+		})
+	}
+}
+
+func TestPrintFiles(t *testing.T) {
+	var buf bytes.Buffer
+	err := printFiles(&buf, []chunkify.APIFile{
+		{Path: "job_1/job_1.m3u8", Size: 512, MimeType: "application/vnd.apple.mpegurl"},
+		{Path: "job_1/job_1_0.ts", Size: 2 * 1024 * 1024, MimeType: "video/mp2t", Width: 1280, Height: 720, Duration: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"job_1/job_1.m3u8", "512B", "video/mp2t", "1280x720", "2MB", "Total: 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output:\n%s", want, out)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/chunkifydev/cli/pkg/formatter"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
@@ -25,6 +26,17 @@ const (
 	FormatJpg     = "jpg"
 )
 
+var formats = []string{
+	FormatMp4H264,
+	FormatMp4H265,
+	FormatMp4Av1,
+	FormatWebmVp9,
+	FormatHlsH264,
+	FormatHlsH265,
+	FormatHlsAv1,
+	FormatJpg,
+}
+
 // Transcoder flags
 var (
 	transcoders    = new(int64)
@@ -34,6 +46,7 @@ var (
 // Storage flags
 var (
 	storagePath = new(string)
+	storageID   = new(string)
 )
 
 // Common video flags
@@ -86,6 +99,7 @@ var (
 	hlsEncKey      = new(string)
 	hlsEncKeyUrl   = new(string)
 	hlsEncIv       = new(string)
+	ladder         = new(string)
 )
 
 // JPG
@@ -94,19 +108,41 @@ var (
 	sprite   = new(bool)
 )
 
+// Preset flags
+var (
+	usePreset = new(string)
+)
+
 // BindFlags attaches root-level flags used by the root command
 func BindFlags(app *App, cmd *cobra.Command) {
 	app.Command = &ChunkifyCommand{Id: uuid.New().String()}
 
 	cmd.Flags().BoolVar(&app.JSON, "json", false, "Output in JSON format")
 	cmd.Flags().StringVarP(&app.Command.Input, "input", "i", "", "Input video to transcode. It can be a file, HTTP URL or source ID (src_*)")
-	cmd.Flags().StringVarP(&app.Command.Output, "output", "o", "", "Output file path")
+	cmd.Flags().StringVarP(&app.Command.Output, "output", "o", "", "Output file path. It can contain placeholders: {input_name}, {height}, {format}, {ext}, {job_id}, {source_id}, {date} and {profile}")
+	cmd.Flags().BoolVar(&app.Command.Detach, "detach", false, "Exit once the job is created and print its ID, without waiting for it")
+	bindOnInterruptFlag(app, cmd)
+
+	bindTranscodeFlags(app, cmd)
+
+	cmd.MarkFlagRequired("input")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return prepareCommand(app, cmd.Flags())
+	}
+}
+
+// bindTranscodeFlags attaches the format and transcoding flags shared by all the commands creating jobs
+func bindTranscodeFlags(app *App, cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&app.Command.Format, "format", "f", "", "Output format (mp4/h264, mp4/h265, mp4/av1, webm/vp9, hls/h264, hls/h265, hls/av1, jpg)")
+	cmd.Flags().IntVar(&app.Command.DownloadConcurrency, "download-concurrency", 4, "Number of files to download at the same time (1-32)")
 
 	cmd.Flags().Int64Var(transcoders, "transcoders", 0, "Number of transcoders to use")
 	cmd.Flags().Int64Var(transcoderVcpu, "vcpu", 0, "vCPU per transcoder (4, 8, or 16)")
 
 	cmd.Flags().StringVar(storagePath, "storage-path", "", "Storage absolute path")
+	cmd.Flags().StringVar(storageID, "storage-id", "", "ID of the storage where the outputs are stored (see chunkify storages list). The project's default storage is used otherwise")
+	cmd.Flags().BoolVar(&app.Command.NoDownload, "no-download", false, "Leave the outputs on the storage and print their locations instead of downloading them")
 
 	// Common video settings
 	cmd.Flags().StringVarP(resolution, "resolution", "s", "", "Set resolution wxh (0-8192x0-8192)")
@@ -143,29 +179,50 @@ func BindFlags(app *App, cmd *cobra.Command) {
 	cmd.Flags().StringVar(hlsEncKey, "hls-enc-key", "", "Set HLS encryption key")
 	cmd.Flags().StringVar(hlsEncKeyUrl, "hls-enc-key-url", "", "Set HLS encryption key URL")
 	cmd.Flags().StringVar(hlsEncIv, "hls-enc-iv", "", "Set HLS encryption IV")
+	cmd.Flags().StringVar(ladder, "ladder", "", "Create one HLS rendition per <height>p:<bitrate> sharing the same manifest (e.g. 1080p:5M,720p:2.5M,480p:1M)")
 
 	// JPG flags
 	cmd.Flags().Int64Var(interval, "interval", 0, "Set frame extraction interval in seconds (1-60)")
 	cmd.Flags().BoolVar(sprite, "sprite", false, "Generate sprite sheet")
 
-	cmd.MarkFlagRequired("input")
+	cmd.Flags().StringVar(usePreset, "use-preset", "", "Use the settings of a saved preset (see chunkify preset). Flags override the preset values")
 
 	cmd.MarkFlagsRequiredTogether("transcoders", "vcpu")
+}
 
-	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
-		if err := setupCommand(app); err != nil {
+// prepareCommand applies the preset, validates the flags and builds the job params of the command
+func prepareCommand(app *App, flags *pflag.FlagSet) error {
+	if usePreset != nil && *usePreset != "" {
+		if err := applyPreset(*usePreset, flags); err != nil {
 			return err
 		}
+	}
 
-		if err := validateTranscodeSettings(app); err != nil {
-			return err
-		}
+	if app.Command.DownloadConcurrency < 1 || app.Command.DownloadConcurrency > 32 {
+		return flagErrorf("download-concurrency", "--download-concurrency must be between 1 and 32")
+	}
 
-		// build job format params according to all format flags
-		setJobFormatParams(app)
+	if err := validateOnInterrupt(app.Command.OnInterrupt); err != nil {
+		return err
+	}
 
-		return nil
+	if err := setupCommand(app); err != nil {
+		return err
+	}
+
+	if err := validateNoDownload(app.Command); err != nil {
+		return err
+	}
+
+	if err := validateTranscodeSettings(app); err != nil {
+		return err
 	}
+
+	// build job format params according to all format flags
+	setJobFormatParams(app)
+	setLadderFormatParams(app)
+
+	return nil
 }
 
 func setupCommand(app *App) error {
@@ -174,8 +231,16 @@ func setupCommand(app *App) error {
 		return nil
 	}
 
+	if err := validateOutputTemplate(app.Command.Output); err != nil {
+		return err
+	}
+
 	// Set default format based on output file extension
 	if app.Command.Format == "" {
+		// {ext} is resolved from the format, it can't be used to infer it
+		if strings.Contains(app.Command.Output, "{ext}") {
+			return flagErrorf("output", "{ext} can only be used in the output with --format")
+		}
 		switch path.Ext(app.Command.Output) {
 		case ".mp4":
 			app.Command.Format = FormatMp4H264
@@ -186,8 +251,27 @@ func setupCommand(app *App) error {
 		case ".jpg":
 			app.Command.Format = FormatJpg
 		default:
-			return fmt.Errorf("invalid output file extension: %s. Please provide a valid format with --format", path.Ext(app.Command.Output))
+			return flagErrorf("output", "invalid output file extension: %s. Please provide a valid format with --format", path.Ext(app.Command.Output))
+		}
+	}
+
+	// Parse the HLS ladder, each rendition gets its own height and video bitrate
+	if ladder != nil && *ladder != "" {
+		if !strings.HasPrefix(app.Command.Format, "hls") {
+			return flagErrorf("ladder", "--ladder can only be used with hls formats")
+		}
+		if (resolution != nil && *resolution != "") || (videoBitrateStr != nil && *videoBitrateStr != "") {
+			return flagErrorf("ladder", "--ladder can't be used with --resolution or --vb")
+		}
+		if err := validateLadderOutput(app.Command.Output); err != nil {
+			return err
 		}
+
+		renditions, err := parseLadder(*ladder, app.Command.Output)
+		if err != nil {
+			return err
+		}
+		app.Command.Renditions = renditions
 	}
 
 	// Set the number of transcoders and their type
@@ -198,27 +282,31 @@ func setupCommand(app *App) error {
 		}
 	}
 
-	// Set the storage path
+	// Set the storage and the path of the outputs on it
 	if storagePath != nil && *storagePath != "" {
-		app.Command.JobCreateStorageParams = chunkify.JobNewParamsStorage{
-			Path: chunkify.String(*storagePath),
+		app.Command.JobCreateStorageParams.Path = chunkify.String(*storagePath)
+	}
+	if storageID != nil && *storageID != "" {
+		if err := validateStorageID(*storageID); err != nil {
+			return err
 		}
+		app.Command.JobCreateStorageParams.ID = chunkify.String(*storageID)
 	}
 
 	// shortcut to set width and height from resolution flag
 	if resolution != nil && *resolution != "" {
 		parts := strings.Split(*resolution, "x")
 		if len(parts) != 2 {
-			return fmt.Errorf("invalid resolution: %s", *resolution)
+			return flagErrorf("resolution", "invalid resolution: %s", *resolution)
 		}
 
 		resWidth, err := strconv.ParseInt(parts[0], 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid width: %s", parts[0])
+			return flagErrorf("resolution", "invalid width: %s", parts[0])
 		}
 		resHeight, err := strconv.ParseInt(parts[1], 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid height: %s", parts[1])
+			return flagErrorf("resolution", "invalid height: %s", parts[1])
 		}
 		width = &resWidth
 		height = &resHeight
@@ -230,7 +318,7 @@ func setupCommand(app *App) error {
 	if videoBitrateStr != nil && *videoBitrateStr != "" {
 		videoBitrateInt, err := formatter.ParseFileSize(*videoBitrateStr)
 		if err != nil {
-			return fmt.Errorf("invalid video bitrate: %s", *videoBitrateStr)
+			return flagErrorf("vb", "invalid video bitrate: %s", *videoBitrateStr)
 		}
 		videoBitrate = &videoBitrateInt
 	}
@@ -238,7 +326,7 @@ func setupCommand(app *App) error {
 	if audioBitrateStr != nil && *audioBitrateStr != "" {
 		audioBitrateInt, err := formatter.ParseFileSize(*audioBitrateStr)
 		if err != nil {
-			return fmt.Errorf("invalid audio bitrate: %s", *audioBitrateStr)
+			return flagErrorf("ab", "invalid audio bitrate: %s", *audioBitrateStr)
 		}
 		audioBitrate = &audioBitrateInt
 	}
@@ -246,7 +334,7 @@ func setupCommand(app *App) error {
 	if maxrateStr != nil && *maxrateStr != "" {
 		maxrateInt, err := formatter.ParseFileSize(*maxrateStr)
 		if err != nil {
-			return fmt.Errorf("invalid maximum bitrate: %s", *maxrateStr)
+			return flagErrorf("maxrate", "invalid maximum bitrate: %s", *maxrateStr)
 		}
 		maxrate = &maxrateInt
 	}
@@ -254,7 +342,7 @@ func setupCommand(app *App) error {
 	if bufsizeStr != nil && *bufsizeStr != "" {
 		bufsizeInt, err := formatter.ParseFileSize(*bufsizeStr)
 		if err != nil {
-			return fmt.Errorf("invalid buffer size: %s", *bufsizeStr)
+			return flagErrorf("bufsize", "invalid buffer size: %s", *bufsizeStr)
 		}
 		bufsize = &bufsizeInt
 	}
@@ -270,23 +358,18 @@ func validateTranscodeSettings(app *App) error {
 	}
 
 	// Check if the format is valid
-	if !slices.Contains([]string{
-		FormatMp4H264,
-		FormatMp4H265,
-		FormatMp4Av1,
-		FormatWebmVp9,
-		FormatHlsH264,
-		FormatHlsH265,
-		FormatHlsAv1,
-		FormatJpg,
-	}, app.Command.Format) {
-		return fmt.Errorf("invalid format: %s", app.Command.Format)
+	if !slices.Contains(formats, app.Command.Format) {
+		return flagErrorf("format", "invalid format: %s", app.Command.Format)
 	}
 
 	if err := validateCommonVideoFlags(); err != nil {
 		return err
 	}
 
+	if err := validateLadder(app.Command.Renditions); err != nil {
+		return err
+	}
+
 	// validate format settings according to the format
 	switch app.Command.Format {
 	case FormatMp4H264:
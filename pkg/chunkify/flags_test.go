@@ -1,6 +1,7 @@
 package chunkify
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -9,6 +10,7 @@ func resetGlobalFlags() {
 	transcoders = nil
 	transcoderVcpu = nil
 	storagePath = nil
+	storageID = nil
 	resolution = nil
 	width = nil
 	height = nil
@@ -39,6 +41,25 @@ func resetGlobalFlags() {
 	hlsTime = nil
 	hlsSegmentType = nil
 	interval = nil
+	ladder = nil
+}
+
+// Helper function to allocate all global variables so the flags can be bound again
+func newGlobalFlags() {
+	transcoders, transcoderVcpu = new(int64), new(int64)
+	storagePath, storageID = new(string), new(string)
+	resolution, pixfmt = new(string), new(string)
+	width, height, gop, channels, duration, seek = new(int64), new(int64), new(int64), new(int64), new(int64), new(int64)
+	framerate = new(float64)
+	disableAudio, disableVideo = new(bool), new(bool)
+	maxrate, bufsize, videoBitrate, audioBitrate = new(int64), new(int64), new(int64), new(int64)
+	maxrateStr, bufsizeStr, videoBitrateStr, audioBitrateStr = new(string), new(string), new(string), new(string)
+	crf, level, x264KeyInt, x265KeyInt = new(int64), new(int64), new(int64), new(int64)
+	preset, profilev, quality, cpuUsed = new(string), new(string), new(string), new(string)
+	hlsManifestId, hlsSegmentType, hlsEncKey, hlsEncKeyUrl, hlsEncIv, ladder = new(string), new(string), new(string), new(string), new(string), new(string)
+	hlsTime, interval = new(int64), new(int64)
+	hlsEnc, sprite = new(bool), new(bool)
+	usePreset = new(string)
 }
 
 func TestSetupCommand_NoFormatOrOutput(t *testing.T) {
@@ -129,6 +150,21 @@ func TestSetupCommand_InvalidOutputExtension(t *testing.T) {
 	}
 }
 
+func TestSetupCommand_ExtWithoutFormat(t *testing.T) {
+	resetGlobalFlags()
+
+	app := &App{Command: &ChunkifyCommand{Output: "out.{ext}"}}
+	err := setupCommand(app)
+	if err == nil || err.Error() != "{ext} can only be used in the output with --format" {
+		t.Errorf("Expected an error for {ext} without --format, got %v", err)
+	}
+
+	app = &App{Command: &ChunkifyCommand{Format: FormatWebmVp9, Output: "out.{ext}"}}
+	if err := setupCommand(app); err != nil {
+		t.Errorf("Expected {ext} to be accepted with --format, got %v", err)
+	}
+}
+
 func TestSetupCommand_InvalidFormat(t *testing.T) {
 	resetGlobalFlags()
 
@@ -370,4 +406,55 @@ func TestSetupCommand_StorageParams(t *testing.T) {
 	if app.Command.JobCreateStorageParams.Path.Value != storageVal {
 		t.Errorf("Expected Path %s, got %v", storageVal, app.Command.JobCreateStorageParams.Path.Value)
 	}
+	if app.Command.JobCreateStorageParams.ID.Valid() {
+		t.Errorf("Expected no storage ID, got %v", app.Command.JobCreateStorageParams.ID.Value)
+	}
+}
+
+func TestSetupCommand_StorageID(t *testing.T) {
+	resetGlobalFlags()
+
+	id := "aws-prod"
+	storageID = &id
+
+	app := &App{Command: &ChunkifyCommand{Format: FormatMp4H264}}
+	if err := setupCommand(app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if app.Command.JobCreateStorageParams.ID.Value != id || app.Command.JobCreateStorageParams.Path.Valid() {
+		t.Errorf("Unexpected storage params: %+v", app.Command.JobCreateStorageParams)
+	}
+
+	invalid := "s3"
+	storageID = &invalid
+	app = &App{Command: &ChunkifyCommand{Format: FormatMp4H264}}
+	if err := setupCommand(app); err == nil || !strings.Contains(err.Error(), "invalid --storage-id") {
+		t.Errorf("Expected an invalid --storage-id error, got %v", err)
+	}
+}
+
+func TestValidateNoDownload(t *testing.T) {
+	tests := []struct {
+		name    string
+		command ChunkifyCommand
+		wantErr string
+	}{
+		{name: "download", command: ChunkifyCommand{Output: "video.mp4"}},
+		{name: "format", command: ChunkifyCommand{NoDownload: true, Format: FormatHlsH264}},
+		{name: "no format", command: ChunkifyCommand{NoDownload: true}, wantErr: "requires --format"},
+		{name: "output", command: ChunkifyCommand{NoDownload: true, Format: FormatMp4H264, Output: "video.mp4"}, wantErr: "can't be used with --output"},
+		{name: "detach", command: ChunkifyCommand{NoDownload: true, Format: FormatMp4H264, Detach: true}, wantErr: "can't be used with --detach"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNoDownload(&tt.command)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
 }
@@ -10,30 +10,93 @@ import (
 
 func ProcessM3u8(downloadedFiles []string, basename string, oldManifestContent []byte) error {
 	var (
-		m3u8Content, manifestContent []byte
-		videoBasename                string
-		m3u8Path, manifestPath       string
-		err                          error
+		manifestContent []byte
+		manifestPath    string
+		err             error
+	)
+
+	for _, filepath := range downloadedFiles {
+		if path.Ext(filepath) == ".m3u8" && strings.HasSuffix(filepath, "manifest.m3u8") {
+			manifestPath = filepath
+			manifestContent, err = os.ReadFile(filepath)
+			if err != nil {
+				return fmt.Errorf("read file: %w", err)
+			}
+			if len(oldManifestContent) > 0 {
+				manifestContent = mergeManifest(manifestContent, oldManifestContent)
+			}
+		}
+	}
+
+	videoBasename, err := processPlaylist(downloadedFiles, basename)
+	if err != nil {
+		return err
+	}
+
+	manifestContent = []byte(strings.ReplaceAll(string(manifestContent), basename, videoBasename))
+	if err := os.WriteFile(manifestPath, manifestContent, 0644); err != nil {
+		return fmt.Errorf("write manifest file: %w", err)
+	}
+	return nil
+}
+
+// Rendition holds the downloaded files of one job of an HLS ladder
+type Rendition struct {
+	Basename        string   // Name given by Chunkify to the job files
+	DownloadedFiles []string // Local files of the rendition, manifest excluded
+}
+
+// ProcessHlsLadder renames the playlist of every rendition and
+// writes the shared manifest once, referencing all of them
+func ProcessHlsLadder(renditions []Rendition, manifestPath string) error {
+	var manifestContent []byte
+	var err error
+
+	if manifestPath != "" {
+		manifestContent, err = os.ReadFile(manifestPath)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+	}
+
+	for _, r := range renditions {
+		videoBasename, err := processPlaylist(r.DownloadedFiles, r.Basename)
+		if err != nil {
+			return err
+		}
+		manifestContent = []byte(strings.ReplaceAll(string(manifestContent), r.Basename, videoBasename))
+	}
+
+	if manifestPath == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(manifestPath, manifestContent, 0644); err != nil {
+		return fmt.Errorf("write manifest file: %w", err)
+	}
+	return nil
+}
+
+// processPlaylist renames the segments referenced in the rendition playlist
+// and returns the local basename of the rendition
+func processPlaylist(downloadedFiles []string, basename string) (string, error) {
+	var (
+		m3u8Content   []byte
+		videoBasename string
+		m3u8Path      string
+		err           error
 	)
 
 	for _, filepath := range downloadedFiles {
 		switch path.Ext(filepath) {
 		case ".m3u8":
 			if strings.HasSuffix(filepath, "manifest.m3u8") {
-				manifestPath = filepath
-				manifestContent, err = os.ReadFile(filepath)
-				if err != nil {
-					return fmt.Errorf("read file: %w", err)
-				}
-				if len(oldManifestContent) > 0 {
-					manifestContent = mergeManifest(manifestContent, oldManifestContent)
-				}
-			} else {
-				m3u8Path = filepath
-				m3u8Content, err = os.ReadFile(filepath)
-				if err != nil {
-					return fmt.Errorf("read file: %w", err)
-				}
+				continue
+			}
+			m3u8Path = filepath
+			m3u8Content, err = os.ReadFile(filepath)
+			if err != nil {
+				return "", fmt.Errorf("read file: %w", err)
 			}
 		case ".mp4":
 			videoBasename = strings.Replace(path.Base(filepath), ".mp4", "", 1)
@@ -42,13 +105,9 @@ func ProcessM3u8(downloadedFiles []string, basename string, oldManifestContent [
 
 	m3u8Content = []byte(strings.ReplaceAll(string(m3u8Content), basename, videoBasename))
 	if err := os.WriteFile(m3u8Path, m3u8Content, 0644); err != nil {
-		return fmt.Errorf("write m3u8 file: %w", err)
+		return "", fmt.Errorf("write m3u8 file: %w", err)
 	}
-	manifestContent = []byte(strings.ReplaceAll(string(manifestContent), basename, videoBasename))
-	if err := os.WriteFile(manifestPath, manifestContent, 0644); err != nil {
-		return fmt.Errorf("write manifest file: %w", err)
-	}
-	return nil
+	return videoBasename, nil
 }
 
 func mergeManifest(manifestContent []byte, oldManifestContent []byte) []byte {
@@ -213,3 +213,58 @@ job_1234.m3u8
 		t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, actual)
 	}
 }
+
+func TestProcessHlsLadder(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"video_720p.m3u8": "#EXTM3U\n#EXTINF:10.0,\njob_720.mp4",
+		"video_720p.mp4":  "dummy",
+		"video_480p.m3u8": "#EXTM3U\n#EXTINF:10.0,\njob_480.mp4",
+		"video_480p.mp4":  "dummy",
+		"manifest.m3u8": `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=2000000,RESOLUTION=1280x720
+job_720.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=1000000,RESOLUTION=854x480
+job_480.m3u8`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	renditions := []Rendition{
+		{
+			Basename:        "job_720",
+			DownloadedFiles: []string{filepath.Join(tempDir, "video_720p.m3u8"), filepath.Join(tempDir, "video_720p.mp4")},
+		},
+		{
+			Basename:        "job_480",
+			DownloadedFiles: []string{filepath.Join(tempDir, "video_480p.m3u8"), filepath.Join(tempDir, "video_480p.mp4")},
+		},
+	}
+
+	if err := ProcessHlsLadder(renditions, filepath.Join(tempDir, "manifest.m3u8")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"video_720p.m3u8": "#EXTM3U\n#EXTINF:10.0,\nvideo_720p.mp4",
+		"video_480p.m3u8": "#EXTM3U\n#EXTINF:10.0,\nvideo_480p.mp4",
+		"manifest.m3u8": `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=2000000,RESOLUTION=1280x720
+video_720p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=1000000,RESOLUTION=854x480
+video_480p.m3u8`,
+	}
+	for name, content := range expected {
+		actual, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if string(actual) != content {
+			t.Errorf("%s content mismatch.\nExpected: %q\nActual: %q", name, content, string(actual))
+		}
+	}
+}
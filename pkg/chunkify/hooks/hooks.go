@@ -15,27 +15,8 @@ const ManifestFileName = "manifest.m3u8"
 func Process(format string, defaultBasename string, files []chunkify.APIFile, downloadedFiles []string) error {
 	// For HLS, we need to merge the previous manifest.m3u8
 	var oldManifestContent []byte
-	var basename string
 
-	for _, file := range files {
-		// we get a sample filename to rename everything with the correct name
-		if basename == "" && path.Ext(file.Path) != ".m3u8" && path.Ext(file.Path) != ".vtt" {
-			basename = path.Base(file.Path)
-			break
-		}
-	}
-
-	slog.Info("Downloaded files", "basename", basename)
-	if basename == "" {
-		basename = defaultBasename
-	}
-
-	if path.Ext(basename) == ".jpg" {
-		parts := strings.Split(basename, "-")
-		basename = strings.Join(parts[0:len(parts)-1], "-")
-	}
-
-	basename = strings.Replace(basename, path.Ext(basename), "", 1)
+	basename := Basename(files, defaultBasename)
 
 	for _, filepath := range downloadedFiles {
 		// check if we have a manifest.m3u8 already
@@ -65,3 +46,29 @@ func Process(format string, defaultBasename string, files []chunkify.APIFile, do
 
 	return nil
 }
+
+// Basename returns the name used by Chunkify for the given job files, without extension.
+// It's the name to replace with the one set in --output
+func Basename(files []chunkify.APIFile, defaultBasename string) string {
+	var basename string
+
+	for _, file := range files {
+		// we get a sample filename to rename everything with the correct name
+		if basename == "" && path.Ext(file.Path) != ".m3u8" && path.Ext(file.Path) != ".vtt" {
+			basename = path.Base(file.Path)
+			break
+		}
+	}
+
+	slog.Info("Downloaded files", "basename", basename)
+	if basename == "" {
+		basename = defaultBasename
+	}
+
+	if path.Ext(basename) == ".jpg" {
+		parts := strings.Split(basename, "-")
+		basename = strings.Join(parts[0:len(parts)-1], "-")
+	}
+
+	return strings.Replace(basename, path.Ext(basename), "", 1)
+}
@@ -0,0 +1,313 @@
+package chunkify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/spf13/cobra"
+)
+
+// Actions on interrupt when jobs are still running on Chunkify, see --on-interrupt
+const (
+	OnInterruptCancel = "cancel"
+	OnInterruptDetach = "detach"
+)
+
+// DefaultOnInterrupt is the action when nobody can be asked: with --json, on SIGTERM, and in batch and watch.
+// The jobs are cancelled so a stopped CI run or container doesn't leave them running on Chunkify
+const DefaultOnInterrupt = OnInterruptCancel
+
+// onInterruptUsage is the help of the --on-interrupt flag of all the commands following jobs
+const onInterruptUsage = "What to do with the running jobs on q, ctrl+c or SIGTERM: cancel or detach. Asked in the TUI by default, cancelled when it can't be asked (--json, SIGTERM, batch, watch)"
+
+// CancelTimeout is how long to wait for Chunkify to confirm the jobs are cancelled
+const CancelTimeout = 30 * time.Second
+
+func validateOnInterrupt(action string) error {
+	if action != "" && action != OnInterruptCancel && action != OnInterruptDetach {
+		return flagErrorf("on-interrupt", "--on-interrupt must be %s or %s", OnInterruptCancel, OnInterruptDetach)
+	}
+	return nil
+}
+
+// bindOnInterruptFlag attaches the --on-interrupt flag
+func bindOnInterruptFlag(app *App, cmd *cobra.Command) {
+	cmd.Flags().StringVar(&app.Command.OnInterrupt, "on-interrupt", "", onInterruptUsage)
+}
+
+// interruptMsg is sent to the TUI when the process receives SIGINT or SIGTERM
+type interruptMsg struct{}
+
+// jobsCancelledMsg is sent to the TUI once Chunkify confirmed the jobs are cancelled
+type jobsCancelledMsg struct {
+	err error
+}
+
+// notifyInterrupts forwards SIGINT and SIGTERM to the TUI until stop is called.
+// Bubble Tea would quit right away otherwise, leaving the jobs running on Chunkify
+func notifyInterrupts(p *tea.Program) (stop func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				p.Send(interruptMsg{})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
+
+// runningJobs returns the jobs that are not completed, failed or cancelled yet
+func (t App) runningJobs() []*chunkify.Job {
+	jobs := t.Jobs
+	if len(jobs) == 0 && t.Job != nil {
+		jobs = []*chunkify.Job{t.Job}
+	}
+
+	running := []*chunkify.Job{}
+	for _, job := range jobs {
+		if job.Status != chunkify.JobStatusCompleted && !jobHasFailed(string(job.Status)) {
+			running = append(running, job)
+		}
+	}
+	return running
+}
+
+// interrupt handles q, ctrl+c and the signals. The jobs still running on Chunkify are cancelled
+// or left running according to --on-interrupt. Without it, the user is asked when possible,
+// else DefaultOnInterrupt applies
+func (t App) interrupt(canAsk bool) (tea.Model, tea.Cmd) {
+	if t.Cancelling {
+		return t, nil
+	}
+	if t.Done || len(t.runningJobs()) == 0 {
+		fmt.Println("\nQuitting...")
+		return t.quit()
+	}
+
+	action := t.Command.OnInterrupt
+	if action == "" {
+		// interrupting again while asking leaves the jobs running
+		if t.Interrupting {
+			return t.detach()
+		}
+		if canAsk && !t.JSON {
+			t.Interrupting = true
+			return t, nil
+		}
+		action = DefaultOnInterrupt
+	}
+
+	if action == OnInterruptDetach {
+		return t.detach()
+	}
+	return t.cancelJobs()
+}
+
+// answerInterrupt handles the keys pressed while the user is asked what to do with the running jobs
+func (t App) answerInterrupt(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "c":
+		return t.cancelJobs()
+	case "d":
+		return t.detach()
+	case "w", "esc":
+		t.Interrupting = false
+	case "q", "ctrl+c":
+		return t.interrupt(true)
+	}
+	return t, nil
+}
+
+// quit stops the workflow and exits the TUI
+func (t App) quit() (tea.Model, tea.Cmd) {
+	t.CancelFunc()
+	return t, tea.Quit
+}
+
+// detach exits the TUI and leaves the jobs running on Chunkify
+func (t App) detach() (tea.Model, tea.Cmd) {
+	t.Interrupting = false
+	t.Status = Detached
+	t.Done = true
+	if t.JSON {
+		fmt.Println(t.JSONView())
+	}
+	return t.quit()
+}
+
+// cancelJobs stops following the jobs and cancels them on Chunkify.
+// The TUI exits once Chunkify confirmed they are cancelled, see jobsCancelled
+func (t App) cancelJobs() (tea.Model, tea.Cmd) {
+	t.Interrupting = false
+	t.Cancelling = true
+	t.CancelFunc()
+
+	ids := []string{}
+	for _, job := range t.runningJobs() {
+		ids = append(ids, job.ID)
+	}
+
+	client := t.Client
+	return t, func() tea.Msg {
+		return jobsCancelledMsg{err: cancelWithTimeout(client, ids)}
+	}
+}
+
+func (t App) jobsCancelled(err error) (tea.Model, tea.Cmd) {
+	t.Cancelling = false
+	t.Done = true
+	t.setCancelled(err)
+
+	if t.JSON {
+		fmt.Println(t.JSONView())
+	}
+	return t, tea.Quit
+}
+
+// setCancelled sets the final status once the jobs are cancelled, or failed to be
+func (t *App) setCancelled(err error) {
+	if err != nil {
+		t.Status = Failed
+		t.Error = fmt.Errorf("the job may still be running on Chunkify: %w", err)
+	} else {
+		t.Status = Cancelled
+	}
+}
+
+// notifyPipelineInterrupts returns a context cancelled on SIGINT or SIGTERM, for the commands running
+// the pipelines without the TUI. The pipelines then handle their running jobs, see interruptPipeline
+func notifyPipelineInterrupts(parent context.Context, action string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sigChan:
+			if action == OnInterruptDetach {
+				fmt.Println("\nInterrupted, leaving the running jobs on Chunkify...")
+			} else {
+				fmt.Println("\nInterrupted, cancelling the running jobs...")
+			}
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigChan)
+		cancel()
+	}
+}
+
+// interruptPipeline handles the running jobs of a pipeline interrupted without the TUI, where
+// nobody can be asked: they are cancelled unless --on-interrupt is detach.
+// With --detach, the jobs are meant to keep running and are left as is
+func (t App) interruptPipeline() App {
+	// the jobs created right before the interrupt may not have been read yet
+	for drained := false; !drained; {
+		select {
+		case job := <-t.Progress.JobProgress:
+			t.Job = &job
+		case jobs := <-t.Progress.LadderProgress:
+			if len(jobs) > 0 {
+				t.Jobs = jobs
+				t.Job = jobs[0]
+			}
+		default:
+			drained = true
+		}
+	}
+
+	running := t.runningJobs()
+	if len(running) == 0 || t.Command.Detach {
+		return t
+	}
+
+	if t.Command.OnInterrupt == OnInterruptDetach {
+		t.Status = Detached
+		return t
+	}
+
+	ids := []string{}
+	for _, job := range running {
+		ids = append(ids, job.ID)
+	}
+	t.setCancelled(cancelWithTimeout(t.Client, ids))
+	return t
+}
+
+// cancelWithTimeout cancels the jobs, waiting at most CancelTimeout for Chunkify to confirm it
+func cancelWithTimeout(client *chunkify.Client, ids []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), CancelTimeout)
+	defer cancel()
+	return CancelJobs(ctx, client, ids)
+}
+
+// CancelJobs cancels the jobs and waits until Chunkify reports all of them as cancelled
+func CancelJobs(ctx context.Context, client *chunkify.Client, ids []string) error {
+	errs := []error{}
+	pending := []string{}
+	for _, id := range ids {
+		if err := client.Jobs.Cancel(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("could not cancel job %s: %w", id, err))
+			continue
+		}
+		pending = append(pending, id)
+	}
+
+	ticker := time.NewTicker(ProgressUpdateInterval)
+	defer ticker.Stop()
+
+	for len(pending) > 0 {
+		remaining := []string{}
+		for _, id := range pending {
+			job, err := client.Jobs.Get(ctx, id)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error getting job %s: %w", id, err))
+				continue
+			}
+
+			switch job.Status {
+			case chunkify.JobStatusCancelled:
+			case chunkify.JobStatusCompleted, chunkify.JobStatusFailed:
+				errs = append(errs, fmt.Errorf("job %s is %s", id, job.Status))
+			default:
+				remaining = append(remaining, id)
+			}
+		}
+		pending = remaining
+
+		if len(pending) > 0 {
+			select {
+			case <-ctx.Done():
+				for _, id := range pending {
+					errs = append(errs, fmt.Errorf("timed out waiting for job %s to be cancelled", id))
+				}
+				pending = nil
+			case <-ticker.C:
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
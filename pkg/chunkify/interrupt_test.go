@@ -0,0 +1,159 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// newTestCancelClient returns a client of a test server where job_1 is cancelled after being polled once
+// and job_done can't be cancelled as it is already completed
+func newTestCancelClient(t *testing.T) *chunkify.Client {
+	t.Helper()
+	polls := 0
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/jobs/job_1/cancel":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/jobs/job_1":
+			status := "transcoding"
+			if polls++; polls > 1 {
+				status = "cancelled"
+			}
+			fmt.Fprintf(w, `{"data":{"id":"job_1","status":%q}}`, status)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"job is already completed"}}`)
+		}
+	})
+	return client
+}
+
+func TestCancelJobs(t *testing.T) {
+	client := newTestCancelClient(t)
+
+	if err := CancelJobs(context.Background(), client, []string{"job_1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CancelJobs(context.Background(), client, []string{"job_done"})
+	if err == nil || !strings.Contains(err.Error(), "could not cancel job job_done") {
+		t.Errorf("expected an error for job_done, got %v", err)
+	}
+}
+
+func newTestInterruptApp(onInterrupt string, status chunkify.JobStatus) App {
+	ctx, cancel := context.WithCancel(context.Background())
+	app := NewApp()
+	app.Ctx, app.CancelFunc = ctx, cancel
+	app.Command = &ChunkifyCommand{Format: FormatMp4H264, OnInterrupt: onInterrupt}
+	app.Status = Transcoding
+	app.Job = &chunkify.Job{ID: "job_1", Status: status}
+	return *app
+}
+
+func TestInterrupt(t *testing.T) {
+	t.Run("ask", func(t *testing.T) {
+		app := newTestInterruptApp("", chunkify.JobStatusTranscoding)
+
+		m, cmd := app.interrupt(true)
+		if !m.(App).Interrupting || cmd != nil {
+			t.Fatal("expected the user to be asked")
+		}
+
+		m, _ = m.(App).answerInterrupt("w")
+		if m.(App).Interrupting || m.(App).Done {
+			t.Fatal("expected to keep waiting")
+		}
+
+		m, _ = m.(App).answerInterrupt("d")
+		if m.(App).Status != Detached || app.Ctx.Err() == nil {
+			t.Errorf("expected the app to detach")
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		app := newTestInterruptApp("", chunkify.JobStatusTranscoding)
+		app.Client = newTestCancelClient(t)
+
+		m, cmd := app.Update(interruptMsg{})
+		if !m.(App).Cancelling || cmd == nil {
+			t.Fatal("expected the job to be cancelled as nobody can be asked")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		app := newTestInterruptApp(OnInterruptDetach, chunkify.JobStatusTranscoding)
+		app.JSON = true
+
+		m, _ := app.interrupt(true)
+		if m.(App).Status != Detached {
+			t.Errorf("expected --on-interrupt detach to be kept, got status %d", m.(App).Status)
+		}
+	})
+
+	t.Run("job completed", func(t *testing.T) {
+		app := newTestInterruptApp(OnInterruptCancel, chunkify.JobStatusCompleted)
+
+		m, cmd := app.interrupt(true)
+		if m.(App).Cancelling || cmd == nil || app.Ctx.Err() == nil {
+			t.Errorf("expected the app to quit without cancelling the job")
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		app := newTestInterruptApp(OnInterruptCancel, chunkify.JobStatusTranscoding)
+		app.Client = newTestCancelClient(t)
+
+		m, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		if !m.(App).Cancelling || cmd == nil {
+			t.Fatal("expected the job to be cancelled")
+		}
+
+		m, _ = m.Update(cmd())
+		if m.(App).Status != Cancelled || m.(App).Error != nil {
+			t.Errorf("expected the job to be cancelled, got status %d and error %v", m.(App).Status, m.(App).Error)
+		}
+	})
+}
+
+func TestInterruptPipeline(t *testing.T) {
+	t.Run("cancel", func(t *testing.T) {
+		app := newTestInterruptApp("", chunkify.JobStatusTranscoding)
+		app.Client = newTestCancelClient(t)
+		app.Job = nil
+		// the job created right before the interrupt is still in the channel
+		app.Progress.JobProgress <- chunkify.Job{ID: "job_1", Status: chunkify.JobStatusQueued}
+
+		final := app.interruptPipeline()
+		if final.Status != Cancelled || final.Error != nil {
+			t.Errorf("expected the job to be cancelled, got status %d and error %v", final.Status, final.Error)
+		}
+	})
+
+	t.Run("detach", func(t *testing.T) {
+		app := newTestInterruptApp(OnInterruptDetach, chunkify.JobStatusTranscoding)
+
+		final := app.interruptPipeline()
+		if final.Status != Detached {
+			t.Errorf("expected the job to be left running, got status %d", final.Status)
+		}
+	})
+
+	t.Run("cancel failed", func(t *testing.T) {
+		app := newTestInterruptApp(OnInterruptCancel, chunkify.JobStatusTranscoding)
+		app.Client = newTestCancelClient(t)
+		app.Job.ID = "job_done"
+
+		final := app.interruptPipeline()
+		if final.Status != Failed || final.Error == nil {
+			t.Errorf("expected the cancel error to be reported, got status %d", final.Status)
+		}
+	})
+}
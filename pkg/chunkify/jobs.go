@@ -0,0 +1,371 @@
+package chunkify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+var jobStatusFilters = []string{
+	string(chunkify.JobListParamsStatusQueued),
+	string(chunkify.JobListParamsStatusProcessing),
+	string(chunkify.JobListParamsStatusCompleted),
+	string(chunkify.JobListParamsStatusFailed),
+	string(chunkify.JobListParamsStatusCancelled),
+}
+
+// JobListFilters holds the filters of the jobs list command
+type JobListFilters struct {
+	Status        string
+	Format        string
+	SourceID      string
+	Metadata      []string // key=value pairs the jobs metadata must contain
+	CreatedAfter  string   // date, RFC3339 time or duration ago
+	CreatedBefore string   // date, RFC3339 time or duration ago
+	Limit         int64    // maximum number of jobs listed, 0 lists all
+}
+
+// jobActionResult is the JSON output of the cancel and delete commands
+type jobActionResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+func NewJobsCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "jobs",
+			Short: "List, inspect, cancel and delete jobs",
+			Long: `List, inspect, cancel and delete the jobs of the project
+
+Examples:
+
+chunkify jobs list --status processing
+chunkify jobs get job_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify jobs cancel job_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify jobs watch job_2G6MJiNz71bHQGNzGwKx5cJwPFS -o video.mp4
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newJobsListCommand(cfg))
+	cmd.Command.AddCommand(newJobsGetCommand(cfg))
+	cmd.Command.AddCommand(newJobsWatchCommand(cfg))
+	cmd.Command.AddCommand(newJobsActionCommand(cfg, "cancel", "Cancel one or more jobs", "cancelled", func(ctx context.Context, client *chunkify.Client, id string) error {
+		return client.Jobs.Cancel(ctx, id)
+	}))
+	cmd.Command.AddCommand(newJobsActionCommand(cfg, "delete", "Delete one or more jobs", "deleted", func(ctx context.Context, client *chunkify.Client, id string) error {
+		return client.Jobs.Delete(ctx, id)
+	}))
+
+	return cmd
+}
+
+func newJobsListCommand(cfg *config.Config) *cobra.Command {
+	filters := JobListFilters{}
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the jobs, most recent first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params, err := filters.Params()
+			if err != nil {
+				return err
+			}
+
+			jobs, err := ListJobs(cmd.Context(), cfg.Client, params, filters.Limit)
+			if err != nil {
+				return fmt.Errorf("error listing jobs: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, jobs)
+			}
+			return printJobs(os.Stdout, jobs)
+		},
+	}
+
+	cmd.Flags().StringVar(&filters.Status, "status", "", "Only list the jobs with the given status: "+strings.Join(jobStatusFilters, ", "))
+	cmd.Flags().StringVarP(&filters.Format, "format", "f", "", "Only list the jobs with the given format: "+strings.Join(formats, ", "))
+	cmd.Flags().StringVar(&filters.SourceID, "source", "", "Only list the jobs of the given source ID")
+	cmd.Flags().StringArrayVar(&filters.Metadata, "metadata", nil, "Only list the jobs with the given metadata, as key=value. Can be repeated")
+	cmd.Flags().StringVar(&filters.CreatedAfter, "created-after", "", "Only list the jobs created after the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().StringVar(&filters.CreatedBefore, "created-before", "", "Only list the jobs created before the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().Int64Var(&filters.Limit, "limit", 0, "Maximum number of jobs to list. All the jobs are listed by default")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newJobsGetCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "get <job_id>",
+		Short:        "Show the details of a job",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			job, err := cfg.Client.Jobs.Get(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("error getting job: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, job)
+			}
+			return printJob(os.Stdout, job)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newJobsWatchCommand(cfg *config.Config) *cobra.Command {
+	app := NewApp()
+	app.Command = &ChunkifyCommand{}
+
+	cmd := &cobra.Command{
+		Use:   "watch <job_id>",
+		Short: "Show the progress of a running job and download its files",
+		Long: `Show the progress of a running job and download its files
+
+Attach to a job started by another execution, e.g. after the terminal was closed during a transcode.
+When the job completes, its files are downloaded if an output is given.
+The {input_name} placeholder of the output is replaced by the source ID.`,
+		Example: "chunkify jobs watch job_2G6MJiNz71bHQGNzGwKx5cJwPFS -o video_1080p.mp4",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if app.Command.DownloadConcurrency < 1 || app.Command.DownloadConcurrency > 32 {
+				return fmt.Errorf("--download-concurrency must be between 1 and 32")
+			}
+			if err := validateOnInterrupt(app.Command.OnInterrupt); err != nil {
+				return err
+			}
+			return validateOutputTemplate(app.Command.Output)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			runApp(app, cfg, func(ctx context.Context) {
+				app.executeJobWatch(ctx, args[0])
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&app.Command.Output, "output", "o", "", "Download the files of the job to this path when it completes. It can contain placeholders like {job_id} or {height}")
+	cmd.Flags().BoolVar(&app.Command.NoDownload, "no-download", false, "Print the storage location of the files when the job completes instead of downloading them")
+	cmd.Flags().IntVar(&app.Command.DownloadConcurrency, "download-concurrency", 4, "Number of files to download at the same time (1-32)")
+	cmd.Flags().BoolVar(&app.JSON, "json", false, "Output in JSON format")
+	bindOnInterruptFlag(app, cmd)
+	cmd.MarkFlagsMutuallyExclusive("output", "no-download")
+
+	return cmd
+}
+
+// executeJobWatch follows an existing job instead of creating the source and the job
+func (app *App) executeJobWatch(ctx context.Context, jobId string) {
+	if err := app.loadJob(ctx, jobId); err != nil {
+		app.setError(err)
+		return
+	}
+	app.Progress.Status <- Transcoding
+
+	app.completeJob(ctx)
+}
+
+// loadJob sets up the app with an existing job and its source, and resolves the output
+func (app *App) loadJob(ctx context.Context, jobId string) error {
+	job, err := app.Client.Jobs.Get(ctx, jobId)
+	if err != nil {
+		return fmt.Errorf("error getting job: %w", err)
+	}
+	app.Job = job
+	app.Command.Format = job.Format.ID
+	app.Command.Input = job.SourceID
+	app.Progress.JobProgress <- *job
+
+	source, err := app.Client.Sources.Get(ctx, job.SourceID)
+	if err != nil {
+		return fmt.Errorf("error getting source: %w", err)
+	}
+	app.Progress.Source <- source
+
+	return app.resolveOutput(source)
+}
+
+// newJobsActionCommand returns a command running action on each given job
+func newJobsActionCommand(cfg *config.Config, use string, short string, done string, action func(ctx context.Context, client *chunkify.Client, id string) error) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          use + " <job_id>...",
+		Short:        short,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := []jobActionResult{}
+			errs := []error{}
+			for _, id := range args {
+				result := jobActionResult{ID: id}
+				if err := action(cmd.Context(), cfg.Client, id); err != nil {
+					result.Error = err.Error()
+					errs = append(errs, fmt.Errorf("could not %s job %s: %w", use, id, err))
+				} else if !jsonOutput {
+					fmt.Printf("%sJob %s %s\n", indent, id, done)
+				}
+				results = append(results, result)
+			}
+
+			if jsonOutput {
+				if err := formatter.PrintJSON(os.Stdout, results); err != nil {
+					return err
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// Params converts the filters to the list params, sorted by creation date, most recent first
+func (f JobListFilters) Params() (chunkify.JobListParams, error) {
+	params := chunkify.JobListParams{
+		Created: chunkify.JobListParamsCreated{Sort: "desc"},
+	}
+
+	if f.Status != "" {
+		if !slices.Contains(jobStatusFilters, f.Status) {
+			return params, fmt.Errorf("invalid status: %s. Expected one of %s", f.Status, strings.Join(jobStatusFilters, ", "))
+		}
+		params.Status = chunkify.JobListParamsStatus(f.Status)
+	}
+
+	if f.Format != "" {
+		if !slices.Contains(formats, f.Format) {
+			return params, fmt.Errorf("invalid format: %s", f.Format)
+		}
+		params.FormatID = chunkify.JobListParamsFormatID(f.Format)
+	}
+
+	if f.SourceID != "" {
+		params.SourceID = chunkify.String(f.SourceID)
+	}
+
+	metadata, err := formatter.ParseMetadata(f.Metadata)
+	if err != nil {
+		return params, err
+	}
+	params.Metadata = metadata
+
+	params.Created.Gte, params.Created.Lte, err = formatter.ParseCreated(f.CreatedAfter, f.CreatedBefore, time.Now())
+	if err != nil {
+		return params, err
+	}
+
+	if f.Limit < 0 {
+		return params, fmt.Errorf("--limit must be positive")
+	}
+
+	return params, nil
+}
+
+// ListJobs returns the jobs matching params, fetching the pages until limit is reached.
+// A limit of 0 returns all the jobs
+func ListJobs(ctx context.Context, client *chunkify.Client, params chunkify.JobListParams, limit int64) ([]chunkify.Job, error) {
+	return formatter.Paginate(limit, func(offset int64, size int64) ([]chunkify.Job, int64, error) {
+		params.Limit = chunkify.Int(size)
+		params.Offset = chunkify.Int(offset)
+		page, err := client.Jobs.List(ctx, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Data, page.Total, nil
+	})
+}
+
+func printJobs(w io.Writer, jobs []chunkify.Job) error {
+	if len(jobs) == 0 {
+		fmt.Fprintln(w, indent+"No job found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tCREATED\tSTATUS\tPROGRESS\tFORMAT\tSOURCE")
+	for _, job := range jobs {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%.f%%\t%s\t%s\n",
+			indent,
+			job.ID,
+			job.CreatedAt.Local().Format(time.DateTime),
+			job.Status,
+			job.Progress,
+			job.Format.ID,
+			job.SourceID)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(jobs))
+	return nil
+}
+
+func printJob(w io.Writer, job *chunkify.Job) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%sID\t%s\n", indent, job.ID)
+	fmt.Fprintf(tw, "%sStatus\t%s\n", indent, job.Status)
+	fmt.Fprintf(tw, "%sProgress\t%.f%%\n", indent, job.Progress)
+	fmt.Fprintf(tw, "%sSource\t%s\n", indent, job.SourceID)
+	fmt.Fprintf(tw, "%sFormat\t%s\n", indent, formatConfig(job.Format))
+	fmt.Fprintf(tw, "%sTranscoders\t%d x %s\n", indent, job.Transcoder.Quantity, job.Transcoder.Type)
+	fmt.Fprintf(tw, "%sStorage\t%s %s\n", indent, formatter.ValueOrDash(job.Storage.ID), job.Storage.Path)
+	if job.HlsManifestID != "" {
+		fmt.Fprintf(tw, "%sHLS manifest\t%s\n", indent, job.HlsManifestID)
+	}
+	fmt.Fprintf(tw, "%sCreated\t%s\n", indent, job.CreatedAt.Local().Format(time.DateTime))
+	if !job.StartedAt.IsZero() {
+		fmt.Fprintf(tw, "%sStarted\t%s\n", indent, job.StartedAt.Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(tw, "%sUpdated\t%s\n", indent, job.UpdatedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(tw, "%sBillable time\t%s\n", indent, formatter.Duration(job.BillableTime))
+	if job.Error.Message != "" {
+		fmt.Fprintf(tw, "%sError\t%s: %s\n", indent, job.Error.Type, job.Error.Message)
+		if job.Error.Detail != "" {
+			fmt.Fprintf(tw, "%s\t%s\n", indent, job.Error.Detail)
+		}
+	}
+
+	keys := make([]string, 0, len(job.Metadata))
+	for key := range job.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = "Metadata"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s=%s\n", indent, label, key, job.Metadata[key])
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,247 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/chunkify-go/option"
+	"github.com/chunkifydev/cli/pkg/config"
+)
+
+// newTestClient returns a client of a test server and the URL of the server
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*chunkify.Client, string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := chunkify.NewClient(option.WithProjectAccessToken("sk_test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+	return &client, server.URL
+}
+
+func TestListJobs_Pagination(t *testing.T) {
+	total := 230
+	requests := 0
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		jobs := []map[string]string{}
+		for i := offset; i < min(offset+limit, total); i++ {
+			jobs = append(jobs, map[string]string{"id": fmt.Sprintf("job_%d", i)})
+		}
+		data, _ := json.Marshal(jobs)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":%s,"total":%d,"offset":%d}`, data, total, offset)
+	})
+
+	jobs, err := ListJobs(context.Background(), client, chunkify.JobListParams{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != total || requests != 3 {
+		t.Errorf("expected %d jobs in 3 requests, got %d jobs in %d requests", total, len(jobs), requests)
+	}
+	if jobs[total-1].ID != fmt.Sprintf("job_%d", total-1) {
+		t.Errorf("unexpected last job: %s", jobs[total-1].ID)
+	}
+
+	requests = 0
+	jobs, err = ListJobs(context.Background(), client, chunkify.JobListParams{}, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 150 || requests != 2 {
+		t.Errorf("expected 150 jobs in 2 requests, got %d jobs in %d requests", len(jobs), requests)
+	}
+}
+
+func TestJobListFilters_Params(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters JobListFilters
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			filters: JobListFilters{Status: "processing", Format: FormatHlsH264, SourceID: "src_1", Metadata: []string{"origin=chunkify/cli"}, CreatedAfter: "24h"},
+		},
+		{
+			name:    "invalid status",
+			filters: JobListFilters{Status: "running"},
+			wantErr: "invalid status: running",
+		},
+		{
+			name:    "invalid format",
+			filters: JobListFilters{Format: "mp4"},
+			wantErr: "invalid format: mp4",
+		},
+		{
+			name:    "invalid metadata",
+			filters: JobListFilters{Metadata: []string{"origin"}},
+			wantErr: "invalid metadata filter",
+		},
+		{
+			name:    "invalid date",
+			filters: JobListFilters{CreatedBefore: "last week"},
+			wantErr: "invalid --created-before",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := tt.filters.Params()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if params.Status != chunkify.JobListParamsStatusProcessing || params.FormatID != chunkify.JobListParamsFormatIDHlsH264 || params.SourceID.Value != "src_1" {
+				t.Errorf("unexpected params: %+v", params)
+			}
+			if len(params.Metadata) != 1 || params.Metadata[0][1] != "chunkify/cli" || params.Created.Gte.Value == 0 {
+				t.Errorf("unexpected params: %+v", params)
+			}
+		})
+	}
+}
+
+func TestJobsCancel(t *testing.T) {
+	cancelled := []string{}
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/cancel")
+		if r.Method != http.MethodPost || id == "job_done" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"job is already completed"}}`)
+			return
+		}
+		cancelled = append(cancelled, id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cmd := NewJobsCommand(&config.Config{Client: client})
+	cmd.Command.SetArgs([]string{"cancel", "job_1", "job_done", "job_2"})
+	cmd.Command.SilenceErrors = true
+
+	err := cmd.Command.Execute()
+	if err == nil || !strings.Contains(err.Error(), "could not cancel job job_done") {
+		t.Errorf("expected an error for job_done, got %v", err)
+	}
+	if len(cancelled) != 2 || cancelled[0] != "job_1" || cancelled[1] != "job_2" {
+		t.Errorf("expected job_1 and job_2 to be cancelled, got %v", cancelled)
+	}
+}
+
+func TestPrintJob(t *testing.T) {
+	job := &chunkify.Job{
+		ID:       "job_1",
+		Status:   chunkify.JobStatusFailed,
+		SourceID: "src_1",
+		Format:   chunkify.JobFormatUnion{ID: FormatMp4H264, Preset: "fast"},
+		Metadata: map[string]string{"origin": MetadataOrigin},
+	}
+	job.Error.Type = "ffmpeg"
+	job.Error.Message = "invalid data"
+
+	var buf bytes.Buffer
+	if err := printJob(&buf, job); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"job_1", "failed", "src_1", "id=mp4_h264 preset=fast", "ffmpeg: invalid data", "origin=" + MetadataOrigin} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output:\n%s", want, out)
+		}
+	}
+}
+
+// newTestJobClient returns a client of a test server with the job job_1 in the given status and its files
+func newTestJobClient(t *testing.T, status string) *chunkify.Client {
+	t.Helper()
+	var serverURL string
+	client, url := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/jobs/job_1":
+			fmt.Fprintf(w, `{"data":{"id":"job_1","status":%q,"source_id":"src_1","format":{"id":"mp4_h264","height":720}}}`, status)
+		case "/api/sources/src_1":
+			fmt.Fprint(w, `{"data":{"id":"src_1","width":1920,"height":1080}}`)
+		case "/api/jobs/job_1/files":
+			fmt.Fprintf(w, `{"data":[{"id":"file_1","job_id":"job_1","path":"job_1.mp4","size":5,"url":"%s/files/job_1.mp4"}]}`, serverURL)
+		case "/files/job_1.mp4":
+			w.Header().Set("Content-Length", "5")
+			fmt.Fprint(w, "video")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	serverURL = url
+	return client
+}
+
+// runTestWorkflow runs the workflow without the TUI and returns the app in its final state
+func runTestWorkflow(app *App, workflow func(ctx context.Context)) App {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.Ctx, app.CancelFunc = ctx, cancel
+	go workflow(ctx)
+
+	final, done := *app, false
+	for !done {
+		if final, done = final.checkChannels(); !done {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return final
+}
+
+func TestExecuteJobWatch(t *testing.T) {
+	app := NewApp()
+	app.Client = newTestJobClient(t, "completed")
+	app.Command = &ChunkifyCommand{Output: filepath.Join(t.TempDir(), "{job_id}_{height}p.{ext}"), DownloadConcurrency: 1}
+
+	final := runTestWorkflow(app, func(ctx context.Context) {
+		app.executeJobWatch(ctx, "job_1")
+	})
+
+	if final.Error != nil {
+		t.Fatal(final.Error)
+	}
+	if filepath.Base(final.Command.Output) != "job_1_720p.mp4" {
+		t.Errorf("expected the output to be named after the job, got %s", final.Command.Output)
+	}
+	if content, err := os.ReadFile(final.Command.Output); err != nil || string(content) != "video" {
+		t.Errorf("expected the file to be downloaded, got %q, %v", content, err)
+	}
+}
+
+func TestExecuteJobWatch_ProgressError(t *testing.T) {
+	app := NewApp()
+	// the transcoders of the processing job can't be listed
+	app.Client = newTestJobClient(t, "processing")
+	app.Command = &ChunkifyCommand{}
+
+	final := runTestWorkflow(app, func(ctx context.Context) {
+		app.executeJobWatch(ctx, "job_1")
+	})
+
+	if final.Error == nil || !strings.Contains(final.Error.Error(), "error listing the transcoders of job job_1") {
+		t.Errorf("expected the progress error to be reported, got %v", final.Error)
+	}
+}
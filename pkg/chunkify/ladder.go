@@ -0,0 +1,355 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/chunkify/hooks"
+	"github.com/chunkifydev/cli/pkg/formatter"
+)
+
+// Rendition is one rung of an HLS ladder set with --ladder
+type Rendition struct {
+	Name            string                           // Name as given in the ladder, e.g. 1080p
+	Height          int64                            // Output height in pixels
+	VideoBitrate    int64                            // Video bitrate in bits per second
+	Output          string                           // Local path of the rendition playlist
+	JobID           string                           // ID of the job transcoding this rendition
+	JobFormatParams chunkify.JobNewParamsFormatUnion // Format params of the rendition job
+}
+
+// parseLadder parses a ladder definition like "1080p:5M,720p:2.5M,480p:1M".
+// The output of each rendition is derived from output: out/video.m3u8 gives out/video_1080p.m3u8
+func parseLadder(ladder string, output string) ([]Rendition, error) {
+	renditions := []Rendition{}
+	names := map[string]bool{}
+
+	for _, rung := range strings.Split(ladder, ",") {
+		rung = strings.TrimSpace(rung)
+		if rung == "" {
+			continue
+		}
+
+		parts := strings.Split(rung, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid ladder rendition: %s. Expected <height>p:<bitrate> (e.g. 720p:2.5M)", rung)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		renditionHeight, err := strconv.ParseInt(strings.TrimSuffix(name, "p"), 10, 64)
+		if err != nil || !strings.HasSuffix(name, "p") {
+			return nil, fmt.Errorf("invalid ladder height: %s", parts[0])
+		}
+
+		bitrate, err := formatter.ParseFileSize(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ladder bitrate: %s", parts[1])
+		}
+
+		if names[name] {
+			return nil, fmt.Errorf("duplicate ladder rendition: %s", name)
+		}
+		names[name] = true
+
+		renditions = append(renditions, Rendition{
+			Name:         name,
+			Height:       renditionHeight,
+			VideoBitrate: bitrate,
+			Output:       renditionOutput(output, name),
+		})
+	}
+
+	if len(renditions) == 0 {
+		return nil, flagErrorf("ladder", "--ladder must contain at least one rendition")
+	}
+
+	return renditions, nil
+}
+
+// renditionOutput returns the playlist path of a rendition next to the given output
+func renditionOutput(output string, name string) string {
+	ext := path.Ext(output)
+	if ext == "" {
+		ext = ".m3u8"
+	}
+	base := strings.TrimSuffix(path.Base(output), path.Ext(output))
+	return path.Join(path.Dir(output), fmt.Sprintf("%s_%s%s", base, name, ext))
+}
+
+func validateLadder(renditions []Rendition) error {
+	for _, r := range renditions {
+		if r.Height <= 0 || r.Height > 8192 {
+			return flagErrorf("ladder", "--ladder height must be between 1 and 8192")
+		}
+		if r.VideoBitrate < 100000 || r.VideoBitrate > 50000000 {
+			return flagErrorf("ladder", "--ladder bitrate must be between 100000 and 50000000")
+		}
+	}
+	return nil
+}
+
+// setLadderFormatParams derives the format params of each rendition
+// from the params built with the other flags
+func setLadderFormatParams(app *App) {
+	for i, r := range app.Command.Renditions {
+		params := chunkify.JobNewParamsFormatUnion{}
+
+		switch {
+		case app.Command.JobFormatParams.OfHlsH264 != nil:
+			p := *app.Command.JobFormatParams.OfHlsH264
+			p.Height = chunkify.Int(r.Height)
+			p.VideoBitrate = chunkify.Int(r.VideoBitrate)
+			params.OfHlsH264 = &p
+		case app.Command.JobFormatParams.OfHlsH265 != nil:
+			p := *app.Command.JobFormatParams.OfHlsH265
+			p.Height = chunkify.Int(r.Height)
+			p.VideoBitrate = chunkify.Int(r.VideoBitrate)
+			params.OfHlsH265 = &p
+		case app.Command.JobFormatParams.OfHlsAv1 != nil:
+			p := *app.Command.JobFormatParams.OfHlsAv1
+			p.Height = chunkify.Int(r.Height)
+			p.VideoBitrate = chunkify.Int(r.VideoBitrate)
+			params.OfHlsAv1 = &p
+		}
+
+		app.Command.Renditions[i].JobFormatParams = params
+	}
+}
+
+// executeLadder creates one job per rendition sharing the same HLS manifest,
+// waits for all of them and downloads every rendition into the output directory
+func (app *App) executeLadder(ctx context.Context, source *chunkify.Source) {
+	jobs, err := app.CreateLadderJobs(ctx, source)
+	if err != nil {
+		app.setError(err)
+		return
+	}
+	app.Jobs = jobs
+	app.Job = jobs[0]
+	app.Progress.LadderProgress <- jobs
+
+	if err := app.resolveOutput(source); err != nil {
+		app.setError(err)
+		return
+	}
+
+	// The jobs keep running on Chunkify
+	if app.Command.Detach {
+		app.Progress.Status <- Completed
+		return
+	}
+
+	progressErr := make(chan error, 1)
+	go func() {
+		progressErr <- app.StartLadderProgress(ctx, jobs)
+	}()
+
+	select {
+	case err := <-progressErr:
+		if err != nil {
+			app.setError(err)
+			return
+		}
+	case <-ctx.Done():
+		return
+	}
+
+	for _, job := range app.Jobs {
+		if jobHasFailed(string(job.Status)) {
+			app.setError(fmt.Errorf("job %s failed with status: %s: %s", job.ID, job.Status, job.Error.Message))
+			return
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+
+	if app.Command.NoDownload {
+		files, _, err := app.ladderFiles(ctx)
+		if err != nil {
+			app.setError(err)
+			return
+		}
+		app.Progress.Files <- files
+	}
+
+	if app.Command.Output != "" {
+		if err := os.MkdirAll(path.Dir(app.Command.Output), 0755); err != nil {
+			app.setError(fmt.Errorf("create output directory: %w", err))
+			return
+		}
+
+		files, ladderRenditions, err := app.ladderFiles(ctx)
+		if err != nil {
+			app.setError(err)
+			return
+		}
+
+		app.Progress.Files <- files
+		downloadedFiles, err := downloadFiles(ctx, app, files)
+		if err != nil {
+			app.setError(err)
+			return
+		}
+
+		manifestPath := ""
+		for _, f := range downloadedFiles {
+			if path.Base(f) == hooks.ManifestFileName {
+				manifestPath = f
+			}
+		}
+
+		for i := range ladderRenditions {
+			ladderRenditions[i].DownloadedFiles = filterDownloaded(downloadedFiles, ladderRenditions[i].DownloadedFiles)
+		}
+
+		if err := hooks.ProcessHlsLadder(ladderRenditions, manifestPath); err != nil {
+			app.setError(err)
+			return
+		}
+	}
+
+	time.Sleep(1 * time.Second)
+	app.Progress.Status <- Completed
+}
+
+// CreateLadderJobs creates the job of the first rendition to get the HLS manifest ID
+// then creates the other renditions with it
+func (app *App) CreateLadderJobs(ctx context.Context, source *chunkify.Source) ([]*chunkify.Job, error) {
+	app.Progress.Status <- Transcoding
+
+	manifestId := *hlsManifestId
+	jobs := []*chunkify.Job{}
+
+	for i, r := range app.Command.Renditions {
+		params := chunkify.JobNewParams{
+			SourceID:   source.ID,
+			Format:     r.JobFormatParams,
+			Transcoder: app.Command.JobTranscoderParams,
+			Storage:    app.Command.JobCreateStorageParams,
+			Metadata:   app.Command.JobMetadata(map[string]string{"rendition": r.Name}),
+		}
+		if manifestId != "" {
+			params.HlsManifestID = chunkify.String(manifestId)
+		}
+
+		job, err := app.Client.Jobs.New(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("error creating job for rendition %s: %w", r.Name, err)
+		}
+
+		if manifestId == "" {
+			manifestId = job.HlsManifestID
+		}
+
+		app.Command.Renditions[i].JobID = job.ID
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// StartLadderProgress polls all the ladder jobs until every one of them is done.
+// It returns the error that stopped the polling, or ctx.Err() when ctx is cancelled
+func (app *App) StartLadderProgress(ctx context.Context, jobs []*chunkify.Job) error {
+	ticker := time.NewTicker(ProgressUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current := make([]*chunkify.Job, len(jobs))
+			done := true
+
+			for i, j := range jobs {
+				job, err := app.Client.Jobs.Get(ctx, j.ID)
+				if err != nil {
+					return fmt.Errorf("error getting job %s: %w", j.ID, err)
+				}
+				current[i] = job
+				if job.Status != chunkify.JobStatusCompleted && !jobHasFailed(string(job.Status)) {
+					done = false
+				}
+			}
+
+			app.Jobs = current
+			app.Job = current[0]
+			app.Progress.LadderProgress <- current
+
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// ladderFiles lists the files of all ladder jobs.
+// The manifest is shared by all jobs so it's only downloaded once,
+// from the last job which references every rendition
+func (app *App) ladderFiles(ctx context.Context) ([]chunkify.APIFile, []hooks.Rendition, error) {
+	files := []chunkify.APIFile{}
+	ladderRenditions := []hooks.Rendition{}
+	var manifest *chunkify.APIFile
+
+	for _, r := range app.Command.Renditions {
+		res, err := app.Client.Jobs.Files.List(ctx, r.JobID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rendition := hooks.Rendition{Basename: hooks.Basename(res.Data, r.JobID)}
+		for _, file := range res.Data {
+			if path.Base(file.Path) == hooks.ManifestFileName {
+				manifest = &file
+				continue
+			}
+			files = append(files, file)
+			rendition.DownloadedFiles = append(rendition.DownloadedFiles, app.Command.OutputFor(file))
+		}
+		ladderRenditions = append(ladderRenditions, rendition)
+	}
+
+	if manifest != nil {
+		files = append(files, *manifest)
+	}
+
+	slog.Info("Ladder files", "files", files)
+	return files, ladderRenditions, nil
+}
+
+// OutputFor returns the local path of a job file.
+// With a ladder, each file is named after the rendition of its job
+func (c *ChunkifyCommand) OutputFor(file chunkify.APIFile) string {
+	for _, r := range c.Renditions {
+		if r.JobID == file.JobID {
+			return filename(file, r.Output)
+		}
+	}
+	return filename(file, c.Output)
+}
+
+// filterDownloaded keeps the expected files that were actually downloaded
+func filterDownloaded(downloaded []string, expected []string) []string {
+	files := []string{}
+	for _, f := range expected {
+		for _, d := range downloaded {
+			if d == f {
+				files = append(files, f)
+				break
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,244 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestParseLadder(t *testing.T) {
+	tests := []struct {
+		name        string
+		ladder      string
+		output      string
+		expected    []Rendition
+		expectError bool
+	}{
+		{
+			name:   "three_renditions",
+			ladder: "1080p:5M,720p:2.5M,480p:1M",
+			output: "hls/video.m3u8",
+			expected: []Rendition{
+				{Name: "1080p", Height: 1080, VideoBitrate: 5 * 1024 * 1024, Output: "hls/video_1080p.m3u8"},
+				{Name: "720p", Height: 720, VideoBitrate: int64(2.5 * 1024 * 1024), Output: "hls/video_720p.m3u8"},
+				{Name: "480p", Height: 480, VideoBitrate: 1024 * 1024, Output: "hls/video_480p.m3u8"},
+			},
+		},
+		{
+			name:   "spaces_and_uppercase",
+			ladder: " 720P:1200K , 360p:600K",
+			output: "video.m3u8",
+			expected: []Rendition{
+				{Name: "720p", Height: 720, VideoBitrate: 1200 * 1024, Output: "video_720p.m3u8"},
+				{Name: "360p", Height: 360, VideoBitrate: 600 * 1024, Output: "video_360p.m3u8"},
+			},
+		},
+		{
+			name:        "missing_bitrate",
+			ladder:      "1080p",
+			output:      "video.m3u8",
+			expectError: true,
+		},
+		{
+			name:        "invalid_height",
+			ladder:      "hd:5M",
+			output:      "video.m3u8",
+			expectError: true,
+		},
+		{
+			name:        "invalid_bitrate",
+			ladder:      "1080p:fast",
+			output:      "video.m3u8",
+			expectError: true,
+		},
+		{
+			name:        "duplicate_rendition",
+			ladder:      "720p:2M,720p:1M",
+			output:      "video.m3u8",
+			expectError: true,
+		},
+		{
+			name:        "empty",
+			ladder:      ",",
+			output:      "video.m3u8",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renditions, err := parseLadder(tt.ladder, tt.output)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(renditions) != len(tt.expected) {
+				t.Fatalf("Expected %d renditions, got %d", len(tt.expected), len(renditions))
+			}
+
+			for i, r := range renditions {
+				e := tt.expected[i]
+				if r.Name != e.Name || r.Height != e.Height || r.VideoBitrate != e.VideoBitrate || r.Output != e.Output {
+					t.Errorf("Expected rendition %+v, got %+v", e, r)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLadder(t *testing.T) {
+	if err := validateLadder([]Rendition{{Height: 720, VideoBitrate: 2000000}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := validateLadder([]Rendition{{Height: 720, VideoBitrate: 1000}}); err == nil {
+		t.Errorf("Expected error for too low bitrate")
+	}
+	if err := validateLadder([]Rendition{{Height: 10000, VideoBitrate: 2000000}}); err == nil {
+		t.Errorf("Expected error for too high height")
+	}
+	if err := validateLadder([]Rendition{{Height: 0, VideoBitrate: 2000000}}); err == nil {
+		t.Errorf("Expected error for a 0p rendition")
+	}
+}
+
+func TestStartLadderProgress_Error(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"internal error"}}`)
+	})
+
+	app := NewApp()
+	app.Client = client
+	app.Command = &ChunkifyCommand{}
+
+	err := app.StartLadderProgress(context.Background(), []*chunkify.Job{{ID: "job_1"}})
+	if err == nil || !strings.Contains(err.Error(), "error getting job job_1") {
+		t.Errorf("Expected the error to be returned, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := app.StartLadderProgress(ctx, []*chunkify.Job{{ID: "job_1"}}); err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestSetupCommand_Ladder(t *testing.T) {
+	resetGlobalFlags()
+	ladderVal := "720p:2M,480p:1M"
+	ladder = &ladderVal
+	defer func() { ladder = nil }()
+
+	app := &App{
+		Command: &ChunkifyCommand{
+			Format: FormatHlsH264,
+			Output: "hls/video.m3u8",
+		},
+	}
+
+	if err := setupCommand(app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := validateTranscodeSettings(app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	setJobFormatParams(app)
+	setLadderFormatParams(app)
+
+	if len(app.Command.Renditions) != 2 {
+		t.Fatalf("Expected 2 renditions, got %d", len(app.Command.Renditions))
+	}
+
+	params := app.Command.Renditions[1].JobFormatParams.OfHlsH264
+	if params == nil {
+		t.Fatalf("Expected hls_h264 params")
+	}
+	if params.Height.Value != 480 {
+		t.Errorf("Expected height 480, got %d", params.Height.Value)
+	}
+	if params.VideoBitrate.Value != 1024*1024 {
+		t.Errorf("Expected video bitrate %d, got %d", 1024*1024, params.VideoBitrate.Value)
+	}
+
+	// the rendition params are copies, the base params stay untouched
+	if app.Command.JobFormatParams.OfHlsH264.Height.Valid() {
+		t.Errorf("Expected base params to have no height")
+	}
+}
+
+func TestSetupCommand_LadderInvalidFormat(t *testing.T) {
+	resetGlobalFlags()
+	ladderVal := "720p:2M"
+	ladder = &ladderVal
+	defer func() { ladder = nil }()
+
+	app := &App{
+		Command: &ChunkifyCommand{
+			Format: FormatMp4H264,
+			Output: "video.mp4",
+		},
+	}
+
+	if err := setupCommand(app); err == nil {
+		t.Errorf("Expected error when using --ladder with a non hls format")
+	}
+}
+
+func TestOutputFor(t *testing.T) {
+	c := &ChunkifyCommand{
+		Output: "hls/video.m3u8",
+		Renditions: []Rendition{
+			{Name: "720p", Output: "hls/video_720p.m3u8", JobID: "job_720"},
+			{Name: "480p", Output: "hls/video_480p.m3u8", JobID: "job_480"},
+		},
+	}
+
+	tests := []struct {
+		file     chunkify.APIFile
+		expected string
+	}{
+		{chunkify.APIFile{JobID: "job_720", Path: "/out/job_720.mp4"}, "hls/video_720p.mp4"},
+		{chunkify.APIFile{JobID: "job_480", Path: "/out/job_480.m3u8"}, "hls/video_480p.m3u8"},
+		{chunkify.APIFile{JobID: "job_480", Path: "/out/manifest.m3u8"}, "hls/manifest.m3u8"},
+		{chunkify.APIFile{JobID: "job_other", Path: "/out/job_other.mp4"}, "hls/video.mp4"},
+	}
+
+	for _, tt := range tests {
+		if got := c.OutputFor(tt.file); got != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestSetupCommand_LadderOutputDirectory(t *testing.T) {
+	resetGlobalFlags()
+	ladderVal := "720p:2M,480p:1M"
+	ladder = &ladderVal
+	defer func() { ladder = nil }()
+
+	app := &App{
+		Command: &ChunkifyCommand{
+			Format: FormatHlsH264,
+			Output: "hls/{height}/video.m3u8",
+		},
+	}
+
+	if err := setupCommand(app); err == nil {
+		t.Errorf("Expected error when the renditions are in different directories")
+	}
+}
@@ -0,0 +1,342 @@
+package chunkify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Preset is a named set of transcoding flags, used with --use-preset
+type Preset struct {
+	Name  string
+	Flags map[string]string // flag values by flag name
+}
+
+var presetNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// presetDir can be overridden in tests
+var presetDir = func() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "chunkify", "presets"), nil
+}
+
+func presetPath(name string) (string, error) {
+	if !presetNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("invalid preset name: %s. Only letters, digits, - and _ are allowed", name)
+	}
+
+	dir, err := presetDir()
+	if err != nil {
+		return "", fmt.Errorf("preset dir: %w", err)
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// LoadPreset reads the preset with the given name
+func LoadPreset(name string) (*Preset, error) {
+	p, err := presetPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("preset not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read preset: %w", err)
+	}
+
+	preset := &Preset{Name: name, Flags: map[string]string{}}
+	if err := yaml.Unmarshal(data, &preset.Flags); err != nil {
+		return nil, fmt.Errorf("decode preset %s: %w", name, err)
+	}
+	return preset, nil
+}
+
+// SavePreset writes the preset, replacing the existing one with the same name
+func SavePreset(preset *Preset) error {
+	p, err := presetPath(preset.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("create preset dir: %w", err)
+	}
+
+	data, err := yaml.Marshal(preset.Flags)
+	if err != nil {
+		return fmt.Errorf("encode preset: %w", err)
+	}
+
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return fmt.Errorf("write preset: %w", err)
+	}
+	return nil
+}
+
+// DeletePreset removes the preset with the given name
+func DeletePreset(name string) error {
+	p, err := presetPath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("preset not found: %s", name)
+	} else if err != nil {
+		return fmt.Errorf("delete preset: %w", err)
+	}
+	return nil
+}
+
+// ListPresets returns all the presets sorted by name
+func ListPresets() ([]*Preset, error) {
+	dir, err := presetDir()
+	if err != nil {
+		return nil, fmt.Errorf("preset dir: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Preset{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read preset dir: %w", err)
+	}
+
+	presets := []*Preset{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		preset, err := LoadPreset(name)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+
+	return presets, nil
+}
+
+// names returns the flag names of the preset, starting with the format
+func (p *Preset) names() []string {
+	names := make([]string, 0, len(p.Flags))
+	for name := range p.Flags {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "format" || names[j] == "format" {
+			return names[i] == "format"
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Args returns the preset as command line flags
+func (p *Preset) Args() []string {
+	args := []string{}
+	for _, name := range p.names() {
+		args = append(args, presetArg(name, p.Flags[name]))
+	}
+	return args
+}
+
+func presetArg(name string, value string) string {
+	switch value {
+	case "true":
+		return "--" + name
+	case "false":
+		return "--" + name + "=false"
+	}
+	return "--" + name + " " + value
+}
+
+// applyPreset sets the flags from the preset, except the ones given on the command line
+func applyPreset(name string, flags *pflag.FlagSet) error {
+	preset, err := LoadPreset(name)
+	if err != nil {
+		return err
+	}
+
+	for _, flag := range preset.names() {
+		value := preset.Flags[flag]
+		if flags.Lookup(flag) == nil {
+			return fmt.Errorf("preset %s: unknown flag --%s", name, flag)
+		}
+		if flags.Changed(flag) {
+			continue
+		}
+		if err := flags.Set(flag, value); err != nil {
+			return fmt.Errorf("preset %s: invalid value %q for --%s", name, value, flag)
+		}
+	}
+
+	return nil
+}
+
+func NewPresetCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "preset",
+			Short: "Manage the transcoding presets",
+			Long: `Manage the transcoding presets
+
+A preset saves a set of transcoding flags under a name, to use them with --use-preset.
+Flags given on the command line override the preset values.
+
+Examples:
+
+Save a preset
+chunkify preset save hls_720p -f hls_h264 -s 1280x720 -g 120 --x264keyint 120 --vb 1200k --ab 128k
+
+Use it
+chunkify -i video.mp4 -o hls/video.m3u8 --use-preset hls_720p
+chunkify batch videos/ --out hls/ --use-preset hls_720p --vb 1500k
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newPresetSaveCommand())
+	cmd.Command.AddCommand(newPresetListCommand())
+	cmd.Command.AddCommand(newPresetShowCommand())
+	cmd.Command.AddCommand(newPresetDeleteCommand())
+
+	return cmd
+}
+
+func newPresetSaveCommand() *cobra.Command {
+	app := NewApp()
+	app.Command = &ChunkifyCommand{}
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "save <name> [flags]",
+		Short: "Save transcoding flags as a preset",
+		Long: `Save transcoding flags as a preset
+
+The flags are validated like when transcoding a video, so mistakes surface before any upload.
+An existing preset can be used as a base with --use-preset.`,
+		Example: "chunkify preset save hls_720p -f hls_h264 -s 1280x720 -g 120 --x264keyint 120 --vb 1200k --ab 128k",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := presetPath(args[0]); err != nil {
+				return err
+			}
+			if err := prepareCommand(app, cmd.Flags()); err != nil {
+				return err
+			}
+			if app.Command.Format == "" {
+				return fmt.Errorf("--format is required")
+			}
+			return nil
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := LoadPreset(args[0]); err == nil && !force {
+				return fmt.Errorf("preset %s already exists, use --force to replace it", args[0])
+			}
+
+			preset := &Preset{Name: args[0], Flags: map[string]string{}}
+			cmd.Flags().Visit(func(f *pflag.Flag) {
+				if f.Name != "use-preset" && f.Name != "force" {
+					preset.Flags[f.Name] = f.Value.String()
+				}
+			})
+
+			if err := SavePreset(preset); err != nil {
+				return err
+			}
+
+			fmt.Printf("  Preset %s saved: %s\n", preset.Name, strings.Join(preset.Args(), " "))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace the preset if it already exists")
+	bindTranscodeFlags(app, cmd)
+
+	return cmd
+}
+
+func newPresetListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the presets",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presets, err := ListPresets()
+			if err != nil {
+				return err
+			}
+
+			if len(presets) == 0 {
+				fmt.Println("  No preset saved yet. Use `chunkify preset save <name> [flags]` to create one.")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, indent+"NAME\tFLAGS")
+			for _, p := range presets {
+				fmt.Fprintf(tw, "%s%s\t%s\n", indent, p.Name, strings.Join(p.Args(), " "))
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newPresetShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "show <name>",
+		Short:        "Show the flags of a preset",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			preset, err := LoadPreset(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("  %s\n\n", preset.Name)
+			for _, arg := range preset.Args() {
+				fmt.Printf("  %s\n", arg)
+			}
+			return nil
+		},
+	}
+}
+
+func newPresetDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete <name>",
+		Short:        "Delete a preset",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := DeletePreset(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("  Preset %s deleted\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,200 @@
+package chunkify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chunkifydev/cli/pkg/config"
+)
+
+func setTestPresetDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	orig := presetDir
+	presetDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { presetDir = orig })
+}
+
+func TestPreset_SaveLoadDelete(t *testing.T) {
+	setTestPresetDir(t)
+
+	preset := &Preset{Name: "hls_720p", Flags: map[string]string{"format": "hls_h264", "resolution": "1280x720", "vb": "1200k"}}
+	if err := SavePreset(preset); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPreset("hls_720p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Flags, preset.Flags) {
+		t.Errorf("expected %v, got %v", preset.Flags, loaded.Flags)
+	}
+
+	presets, err := ListPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(presets) != 1 || presets[0].Name != "hls_720p" {
+		t.Errorf("unexpected presets: %v", presets)
+	}
+
+	if err := DeletePreset("hls_720p"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPreset("hls_720p"); err == nil || !strings.Contains(err.Error(), "preset not found") {
+		t.Errorf("expected preset not found, got %v", err)
+	}
+}
+
+func TestPreset_InvalidName(t *testing.T) {
+	setTestPresetDir(t)
+
+	if err := SavePreset(&Preset{Name: "../hls", Flags: map[string]string{}}); err == nil {
+		t.Error("expected an error for a name with a path separator")
+	}
+}
+
+func TestPreset_Args(t *testing.T) {
+	preset := &Preset{Flags: map[string]string{"vb": "1200k", "an": "true", "format": "mp4_h264", "crf": "21"}}
+	want := []string{"--format mp4_h264", "--an", "--crf 21", "--vb 1200k"}
+	if got := preset.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPresetSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			args: []string{"-f", "hls_h264", "-s", "1280x720", "--vb", "1200k"},
+		},
+		{
+			name:    "missing format",
+			args:    []string{"--crf", "21"},
+			wantErr: "--format is required",
+		},
+		{
+			name:    "invalid value",
+			args:    []string{"-f", "mp4_h264", "--crf", "99"},
+			wantErr: "--crf must be between 16 and 35",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestPresetDir(t)
+			newGlobalFlags()
+
+			cmd := newPresetSaveCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := cmd.PreRunE(cmd, []string{"test"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err := cmd.RunE(cmd, []string{"test"}); err != nil {
+				t.Fatal(err)
+			}
+			preset, err := LoadPreset("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"format": "hls_h264", "resolution": "1280x720", "vb": "1200k"}
+			if !reflect.DeepEqual(preset.Flags, want) {
+				t.Errorf("expected %v, got %v", want, preset.Flags)
+			}
+
+			if err := cmd.RunE(cmd, []string{"test"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+				t.Errorf("expected an error when the preset exists, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsePreset(t *testing.T) {
+	setTestPresetDir(t)
+	if err := SavePreset(&Preset{Name: "mp4", Flags: map[string]string{"format": "mp4_h264", "crf": "30", "vb": "1M"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	newGlobalFlags()
+	cmd := NewBatchCommand(&config.Config{})
+	if err := cmd.Command.ParseFlags([]string{"--use-preset", "mp4", "--crf", "21", "--out", t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := prepareCommand(cmd.App, cmd.Command.Flags()); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cmd.App.Command
+	if c.Format != FormatMp4H264 {
+		t.Errorf("expected the format of the preset, got %s", c.Format)
+	}
+	params := c.JobFormatParams.OfMP4H264
+	if params == nil || params.Crf.Value != 21 {
+		t.Errorf("expected the crf flag to override the preset, got %+v", params)
+	}
+	if params != nil && params.VideoBitrate.Value != 1024*1024 {
+		t.Errorf("expected the video bitrate of the preset, got %d", params.VideoBitrate.Value)
+	}
+}
+
+func TestUsePreset_BatchOut(t *testing.T) {
+	setTestPresetDir(t)
+	if err := SavePreset(&Preset{Name: "hls_720p", Flags: map[string]string{"format": "hls_h264", "resolution": "1280x720", "vb": "1200k"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	newGlobalFlags()
+	cmd := NewBatchCommand(&config.Config{})
+	if err := cmd.Command.ParseFlags([]string{"--out", t.TempDir(), "--use-preset", "hls_720p"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Command.PreRunE(cmd.Command, []string{"videos/"}); err != nil {
+		t.Fatalf("expected the format of the preset to satisfy --out, got %v", err)
+	}
+	if cmd.App.Command.Format != FormatHlsH264 {
+		t.Errorf("expected the format of the preset, got %s", cmd.App.Command.Format)
+	}
+
+	newGlobalFlags()
+	cmd = NewBatchCommand(&config.Config{})
+	if err := cmd.Command.ParseFlags([]string{"--out", t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Command.PreRunE(cmd.Command, []string{"videos/"}); err == nil || !strings.Contains(err.Error(), "--format is required") {
+		t.Errorf("expected --format to be required without preset, got %v", err)
+	}
+}
+
+func TestUsePreset_UnknownFlag(t *testing.T) {
+	setTestPresetDir(t)
+	if err := SavePreset(&Preset{Name: "old", Flags: map[string]string{"format": "mp4_h264", "bitrate": "2M"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	newGlobalFlags()
+	cmd := NewBatchCommand(&config.Config{})
+	if err := cmd.Command.ParseFlags([]string{"--use-preset", "old"}); err != nil {
+		t.Fatal(err)
+	}
+	err := prepareCommand(cmd.App, cmd.Command.Flags())
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --bitrate") {
+		t.Errorf("expected an unknown flag error, got %v", err)
+	}
+}
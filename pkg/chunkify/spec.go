@@ -0,0 +1,251 @@
+package chunkify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// JobSpec describes a job in a YAML or JSON file, run with `chunkify run job.yaml`
+type JobSpec struct {
+	Input               string            `yaml:"input" json:"input"`
+	Output              string            `yaml:"output" json:"output"`
+	Format              string            `yaml:"format" json:"format"`
+	Params              map[string]any    `yaml:"params" json:"params"` // format parameters named after the CLI flags
+	Transcoder          *TranscoderSpec   `yaml:"transcoder" json:"transcoder"`
+	Storage             *StorageSpec      `yaml:"storage" json:"storage"`
+	Metadata            map[string]string `yaml:"metadata" json:"metadata"`
+	DownloadConcurrency int               `yaml:"download_concurrency" json:"download_concurrency"`
+
+	applied map[string]string // spec key of each flag set from the spec
+}
+
+// TranscoderSpec sets the number of transcoders and their type
+type TranscoderSpec struct {
+	Quantity int64 `yaml:"quantity" json:"quantity"`
+	Vcpu     int64 `yaml:"vcpu" json:"vcpu"`
+}
+
+// StorageSpec sets where the outputs are stored
+type StorageSpec struct {
+	ID   string `yaml:"id" json:"id"`
+	Path string `yaml:"path" json:"path"`
+}
+
+// specFlagKeys maps the flags that are not format parameters to their spec key
+var specFlagKeys = map[string]string{
+	"input":                "input",
+	"output":               "output",
+	"format":               "format",
+	"download-concurrency": "download_concurrency",
+	"transcoders":          "transcoder.quantity",
+	"vcpu":                 "transcoder.vcpu",
+	"storage-path":         "storage.path",
+	"storage-id":           "storage.id",
+}
+
+func NewRunCommand(cfg *config.Config) *Command {
+	app := NewApp()
+
+	cmd := &Command{
+		App:    app,
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "run <spec>",
+			Short: "Transcode a video described in a YAML or JSON spec file",
+			Long: `Transcode a video described in a YAML or JSON spec file
+
+The spec lists the input, the output, the format and its parameters, the transcoders, the storage and the job metadata.
+Parameters are named after the flags of the chunkify command. Relative input and output paths are relative to the spec file.
+Flags given on the command line override the values of the spec.
+
+Example of spec:
+
+input: video.mp4
+output: hls/{input_name}.m3u8
+format: hls_h264
+params:
+  gop: 120
+  x264keyint: 120
+  ab: 128k
+  ladder: 1080p:5M,720p:2.5M,480p:1M
+transcoder:
+  quantity: 10
+  vcpu: 8
+metadata:
+  project: trailers
+
+Examples:
+
+chunkify run job.yaml
+chunkify run job.json --crf 24
+`,
+			Args: cobra.ExactArgs(1),
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				spec, err := LoadJobSpec(args[0])
+				if err != nil {
+					return err
+				}
+
+				if err := spec.Apply(app, cmd.Flags(), filepath.Dir(args[0])); err != nil {
+					return err
+				}
+
+				if err := prepareCommand(app, cmd.Flags()); err != nil {
+					return spec.specError(err)
+				}
+				return nil
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				if app.Command.Detach {
+					runDetached(app, cfg)
+					return
+				}
+				runApp(app, cfg, app.executeWorkflow)
+			},
+		},
+	}
+
+	app.Command = &ChunkifyCommand{Id: uuid.New().String()}
+	cmd.Command.Flags().BoolVar(&app.JSON, "json", false, "Output in JSON format")
+	cmd.Command.Flags().StringVarP(&app.Command.Input, "input", "i", "", "Override the input of the spec")
+	cmd.Command.Flags().StringVarP(&app.Command.Output, "output", "o", "", "Override the output of the spec")
+	cmd.Command.Flags().BoolVar(&app.Command.Detach, "detach", false, "Exit once the job is created and print its ID, without waiting for it")
+	bindOnInterruptFlag(app, cmd.Command)
+	bindTranscodeFlags(app, cmd.Command)
+
+	return cmd
+}
+
+// LoadJobSpec reads a spec file. JSON is used for .json files, YAML otherwise.
+// Unknown keys are rejected
+func LoadJobSpec(specPath string) (*JobSpec, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+
+	spec := &JobSpec{}
+	if strings.ToLower(filepath.Ext(specPath)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(spec); err != nil {
+			return nil, fmt.Errorf("invalid spec %s: %w", specPath, err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(spec); err != nil {
+			return nil, fmt.Errorf("invalid spec %s: %w", specPath, err)
+		}
+	}
+
+	return spec, nil
+}
+
+// Apply sets the command and the flags from the spec. Flags set on the command line are kept.
+// Relative local paths are resolved from dir
+func (s *JobSpec) Apply(app *App, flags *pflag.FlagSet, dir string) error {
+	s.applied = map[string]string{}
+
+	if s.Input == "" && !flags.Changed("input") {
+		return fmt.Errorf("input: required")
+	}
+	if !flags.Changed("input") {
+		app.Command.Input = specPath(s.Input, dir)
+		s.applied["input"] = "input"
+	}
+	if !flags.Changed("output") && s.Output != "" {
+		app.Command.Output = specPath(s.Output, dir)
+		s.applied["output"] = "output"
+	}
+	app.Command.Metadata = s.Metadata
+
+	values := map[string]string{}
+	keys := map[string]string{}
+	if s.Format != "" {
+		values["format"] = s.Format
+	}
+	if s.DownloadConcurrency != 0 {
+		values["download-concurrency"] = fmt.Sprint(s.DownloadConcurrency)
+	}
+	if s.Transcoder != nil {
+		if s.Transcoder.Quantity == 0 || s.Transcoder.Vcpu == 0 {
+			return fmt.Errorf("transcoder: quantity and vcpu must be set together")
+		}
+		values["transcoders"] = fmt.Sprint(s.Transcoder.Quantity)
+		values["vcpu"] = fmt.Sprint(s.Transcoder.Vcpu)
+	}
+	if s.Storage != nil && s.Storage.Path != "" {
+		values["storage-path"] = s.Storage.Path
+	}
+	if s.Storage != nil && s.Storage.ID != "" {
+		values["storage-id"] = s.Storage.ID
+	}
+
+	for key, value := range s.Params {
+		name := strings.ReplaceAll(key, "_", "-")
+		if _, ok := specFlagKeys[name]; ok || name == "json" || flags.Lookup(name) == nil {
+			return fmt.Errorf("params.%s: unknown parameter", key)
+		}
+		values[name] = fmt.Sprint(value)
+		keys[name] = "params." + key
+	}
+
+	// apply in a stable order so errors are deterministic
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if flags.Changed(name) {
+			continue
+		}
+		key, ok := keys[name]
+		if !ok {
+			key = specFlagKeys[name]
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return fmt.Errorf("%s: invalid value %q", key, values[name])
+		}
+		s.applied[name] = key
+	}
+
+	return nil
+}
+
+// specPath resolves a local path of the spec relative to dir
+func specPath(p string, dir string) string {
+	if isRemoteInput(p) || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// specError prefixes a validation error with the spec key causing it.
+// Errors caused by flags given on the command line are returned as is
+func (s *JobSpec) specError(err error) error {
+	var flagErr *flagError
+	if !errors.As(err, &flagErr) {
+		return err
+	}
+
+	key, ok := s.applied[flagErr.Flag]
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%s: %w", key, err)
+}
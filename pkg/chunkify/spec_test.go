@@ -0,0 +1,238 @@
+package chunkify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chunkifydev/cli/pkg/config"
+)
+
+func writeSpec(t *testing.T, name string, content string) string {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return specPath
+}
+
+func TestLoadJobSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "yaml",
+			file: "job.yaml",
+			content: `input: video.mp4
+output: out/video.mp4
+format: mp4_h264
+params:
+  crf: 21
+transcoder:
+  quantity: 4
+  vcpu: 8
+metadata:
+  project: test
+`,
+		},
+		{
+			name:    "json",
+			file:    "job.json",
+			content: `{"input": "video.mp4", "format": "mp4_h264", "params": {"crf": 21}}`,
+		},
+		{
+			name:    "unknown yaml key",
+			file:    "job.yml",
+			content: "input: video.mp4\nformats: mp4_h264\n",
+			wantErr: "formats",
+		},
+		{
+			name:    "unknown json key",
+			file:    "job.json",
+			content: `{"input": "video.mp4", "formats": "mp4_h264"}`,
+			wantErr: "formats",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := LoadJobSpec(writeSpec(t, tt.file, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadJobSpec() error = %v", err)
+			}
+			if spec.Input != "video.mp4" || spec.Format != FormatMp4H264 || spec.Params["crf"] == nil {
+				t.Errorf("unexpected spec: %+v", spec)
+			}
+		})
+	}
+}
+
+func TestRunCommand_Spec(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: `input: video.mp4
+output: out/{input_name}.m3u8
+format: hls_h264
+params:
+  hls_time: 4
+  vb: 2M
+  ab: 128k
+transcoder:
+  quantity: 4
+  vcpu: 8
+storage:
+  path: /videos
+metadata:
+  project: test
+`,
+		},
+		{
+			name:    "invalid param value",
+			content: "input: video.mp4\nformat: mp4_h264\nparams:\n  crf: 99\n",
+			wantErr: "params.crf: --crf must be between 16 and 35",
+		},
+		{
+			name:    "param with underscore",
+			content: "input: video.mp4\nformat: hls_h264\nparams:\n  vb: 2M\n  hls_time: 30\n",
+			wantErr: "params.hls_time: --hls-time must be between 1 and 10",
+		},
+		{
+			name:    "invalid format",
+			content: "input: video.mp4\nformat: mp4_vp8\n",
+			wantErr: "format: invalid format: mp4_vp8",
+		},
+		{
+			name:    "invalid bitrate",
+			content: "input: video.mp4\nformat: hls_h264\nparams:\n  vb: fast\n",
+			wantErr: "params.vb: invalid video bitrate",
+		},
+		{
+			name:    "unknown param",
+			content: "input: video.mp4\nformat: mp4_h264\nparams:\n  bitrate: 2M\n",
+			wantErr: "params.bitrate: unknown parameter",
+		},
+		{
+			name:    "param of another section",
+			content: "input: video.mp4\nformat: mp4_h264\nparams:\n  transcoders: 4\n",
+			wantErr: "params.transcoders: unknown parameter",
+		},
+		{
+			name:    "incomplete transcoder",
+			content: "input: video.mp4\nformat: mp4_h264\ntranscoder:\n  quantity: 4\n",
+			wantErr: "transcoder: quantity and vcpu must be set together",
+		},
+		{
+			name:    "missing input",
+			content: "format: mp4_h264\n",
+			wantErr: "input: required",
+		},
+		{
+			name:    "flag overrides spec",
+			content: "input: video.mp4\nformat: mp4_h264\nparams:\n  crf: 99\n",
+			args:    []string{"--crf", "21"},
+		},
+		{
+			name:    "invalid flag is not a spec error",
+			content: "input: video.mp4\nformat: mp4_h264\n",
+			args:    []string{"--crf", "99"},
+			wantErr: "--crf must be between 16 and 35",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newGlobalFlags()
+			specPath := writeSpec(t, "job.yaml", tt.content)
+
+			cmd := NewRunCommand(&config.Config{})
+			if err := cmd.Command.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := cmd.Command.PreRunE(cmd.Command, []string{specPath})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error starting with %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cmd.App.Command.Input != filepath.Join(filepath.Dir(specPath), "video.mp4") {
+				t.Errorf("expected the input to be relative to the spec, got %s", cmd.App.Command.Input)
+			}
+		})
+	}
+}
+
+func TestRunCommand_SpecParams(t *testing.T) {
+	newGlobalFlags()
+	specPath := writeSpec(t, "job.yaml", `input: src_123
+output: out/video.m3u8
+format: hls_h264
+params:
+  hls_time: 4
+  vb: 2M
+transcoder:
+  quantity: 4
+  vcpu: 8
+storage:
+  id: s3-prod
+  path: /videos
+metadata:
+  project: test
+`)
+
+	cmd := NewRunCommand(&config.Config{})
+	if err := cmd.Command.PreRunE(cmd.Command, []string{specPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cmd.App.Command
+	if c.Input != "src_123" {
+		t.Errorf("expected the source ID to be kept, got %s", c.Input)
+	}
+	if c.JobFormatParams.OfHlsH264 == nil || c.JobFormatParams.OfHlsH264.HlsTime.Value != 4 || c.JobFormatParams.OfHlsH264.VideoBitrate.Value != 2*1024*1024 {
+		t.Errorf("unexpected format params: %+v", c.JobFormatParams.OfHlsH264)
+	}
+	if c.JobTranscoderParams.Quantity.Value != 4 || c.JobTranscoderParams.Type != "8vCPU" {
+		t.Errorf("unexpected transcoder params: %+v", c.JobTranscoderParams)
+	}
+	if c.JobCreateStorageParams.Path.Value != "/videos" || c.JobCreateStorageParams.ID.Value != "s3-prod" {
+		t.Errorf("unexpected storage params: %+v", c.JobCreateStorageParams)
+	}
+
+	metadata := c.JobMetadata(nil)
+	if metadata["project"] != "test" || metadata["origin"] != MetadataOrigin {
+		t.Errorf("unexpected metadata: %v", metadata)
+	}
+}
+
+func TestRunCommand_ExecutionID(t *testing.T) {
+	first := NewRunCommand(&config.Config{})
+	second := NewRunCommand(&config.Config{})
+
+	// the source of an upload is found by execution ID, an empty one would match the sources of the previous runs
+	if first.App.Command.Id == "" || first.App.Command.Id == second.App.Command.Id {
+		t.Errorf("expected a unique execution ID per run, got %q and %q", first.App.Command.Id, second.App.Command.Id)
+	}
+}
@@ -0,0 +1,83 @@
+package chunkify
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// storageIDRegexp matches the IDs accepted by the API for --storage-id
+var storageIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{4,64}$`)
+
+// FileLocation is where an output is stored when it's not downloaded, see --no-download
+type FileLocation struct {
+	JobID     string `json:"job_id"`
+	StorageID string `json:"storage_id"`
+	Path      string `json:"path"`
+	URL       string `json:"url,omitempty"`
+	Size      int64  `json:"size"`
+}
+
+func validateStorageID(id string) error {
+	if id != "" && !storageIDRegexp.MatchString(id) {
+		return flagErrorf("storage-id", "invalid --storage-id: %s. It must be 4 to 64 letters, digits, underscores or hyphens", id)
+	}
+	return nil
+}
+
+// validateNoDownload checks --no-download is used with a format and without anything to download
+func validateNoDownload(c *ChunkifyCommand) error {
+	if !c.NoDownload {
+		return nil
+	}
+	if c.Output != "" {
+		return flagErrorf("no-download", "--no-download can't be used with --output, set the format with --format")
+	}
+	if c.Format == "" {
+		return flagErrorf("no-download", "--no-download requires --format")
+	}
+	if c.Detach {
+		return flagErrorf("no-download", "--no-download can't be used with --detach, list the outputs later with chunkify files list")
+	}
+	return nil
+}
+
+// fileLocations returns the locations of the files on their storage
+func fileLocations(files []chunkify.APIFile) []FileLocation {
+	locations := []FileLocation{}
+	for _, f := range files {
+		locations = append(locations, FileLocation{JobID: f.JobID, StorageID: f.StorageID, Path: f.Path, URL: f.URL, Size: f.Size})
+	}
+	return locations
+}
+
+// fileLocation formats the location of a file as storage_id:path
+func fileLocation(file chunkify.APIFile) string {
+	if file.StorageID == "" {
+		return file.Path
+	}
+	return file.StorageID + ":" + file.Path
+}
+
+// filesLocation returns the location of a single file, or of the directory holding all the files
+func filesLocation(files []chunkify.APIFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+	if len(files) == 1 {
+		return fileLocation(files[0])
+	}
+
+	dir := path.Dir(files[0].Path)
+	for _, f := range files[1:] {
+		for dir != "." && dir != "/" && !strings.HasPrefix(f.Path, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	if !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	return fileLocation(chunkify.APIFile{StorageID: files[0].StorageID, Path: dir})
+}
@@ -0,0 +1,76 @@
+package chunkify
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestExecuteJobWatch_NoDownload(t *testing.T) {
+	app := NewApp()
+	app.Client = newTestJobClient(t, "completed")
+	app.Command = &ChunkifyCommand{NoDownload: true, DownloadConcurrency: 1}
+
+	final := runTestWorkflow(app, func(ctx context.Context) {
+		app.executeJobWatch(ctx, "job_1")
+	})
+
+	if final.Error != nil {
+		t.Fatal(final.Error)
+	}
+	if len(final.Files) != 1 || len(final.DownloadedFiles) != 0 {
+		t.Fatalf("expected 1 file listed and none downloaded, got %d and %d", len(final.Files), len(final.DownloadedFiles))
+	}
+
+	if view := final.storageView(); !strings.Contains(view, "job_1.mp4") || !strings.Contains(view, "/files/job_1.mp4") {
+		t.Errorf("expected the path and the URL of the file, got:\n%s", view)
+	}
+	if view := final.JSONView(); !strings.Contains(view, `"files":[{"job_id":"job_1","storage_id":"","path":"job_1.mp4"`) {
+		t.Errorf("expected the file locations in the JSON output, got %s", view)
+	}
+}
+
+func TestFilesLocation(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []chunkify.APIFile
+		want  string
+	}{
+		{
+			name:  "no file",
+			files: nil,
+			want:  "",
+		},
+		{
+			name:  "single file",
+			files: []chunkify.APIFile{{StorageID: "aws-prod", Path: "videos/job_1/video.mp4"}},
+			want:  "aws-prod:videos/job_1/video.mp4",
+		},
+		{
+			name: "common directory",
+			files: []chunkify.APIFile{
+				{StorageID: "aws-prod", Path: "videos/job_1/video_720p.m3u8"},
+				{StorageID: "aws-prod", Path: "videos/job_1/segments/video_720p_0.ts"},
+			},
+			want: "aws-prod:videos/job_1/",
+		},
+		{
+			name: "no storage",
+			files: []chunkify.APIFile{
+				{Path: "/job_1/a.jpg"},
+				{Path: "/job_2/b.jpg"},
+			},
+			want: "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filesLocation(tt.files); got != tt.want {
+				t.Errorf("filesLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
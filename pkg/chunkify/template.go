@@ -0,0 +1,172 @@
+package chunkify
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// outputPlaceholders can be used in the output path, e.g. -o "{input_name}_{height}p.{ext}"
+var outputPlaceholders = []string{
+	"input_name", // input file name without extension
+	"height",     // output height in pixels
+	"format",     // output format, e.g. mp4_h264
+	"ext",        // extension of the format, e.g. mp4
+	"job_id",     // ID of the job
+	"source_id",  // ID of the source
+	"date",       // current date, e.g. 2024-01-31
+	"profile",    // CLI profile, "default" when not set
+}
+
+var placeholderRegexp = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// outputValues holds the values replacing the placeholders of an output template
+type outputValues struct {
+	InputName string
+	Height    int64
+	Format    string
+	JobID     string
+	SourceID  string
+	Date      time.Time
+	Profile   string
+}
+
+// isOutputTemplate returns true if the output contains placeholders
+func isOutputTemplate(output string) bool {
+	return placeholderRegexp.MatchString(output)
+}
+
+// validateOutputTemplate checks that all the placeholders of the output are known
+func validateOutputTemplate(output string) error {
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(output, -1) {
+		found := false
+		for _, p := range outputPlaceholders {
+			if match[1] == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return flagErrorf("output", "unknown placeholder in output: %s. Available placeholders: {%s}", match[0], strings.Join(outputPlaceholders, "}, {"))
+		}
+	}
+	return nil
+}
+
+// validateLadderOutput checks that the renditions of a ladder output end up in the same directory,
+// which holds the HLS manifest: the placeholders that differ per rendition can only be in the file name
+func validateLadderOutput(output string) error {
+	dir := path.Dir(output)
+	for _, p := range []string{"{height}", "{job_id}"} {
+		if strings.Contains(dir, p) {
+			return flagErrorf("output", "%s can't be in the output directory with --ladder, all the renditions must be in the same directory", p)
+		}
+	}
+	return nil
+}
+
+// expand replaces the placeholders of the template with the values
+func (v outputValues) expand(template string) string {
+	profile := v.Profile
+	if profile == "" {
+		profile = "default"
+	}
+
+	return placeholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		switch strings.Trim(placeholder, "{}") {
+		case "input_name":
+			return v.InputName
+		case "height":
+			return strconv.FormatInt(v.Height, 10)
+		case "format":
+			return v.Format
+		case "ext":
+			return strings.TrimPrefix(formatExt(v.Format), ".")
+		case "job_id":
+			return v.JobID
+		case "source_id":
+			return v.SourceID
+		case "date":
+			return v.Date.Format(time.DateOnly)
+		case "profile":
+			return profile
+		}
+		return placeholder
+	})
+}
+
+// outputHeight returns the height of the output video.
+// When it's not set, it's computed from the source keeping the aspect ratio
+func outputHeight(source *chunkify.Source, outWidth int64, outHeight int64) int64 {
+	if outHeight > 0 {
+		return outHeight
+	}
+	if outWidth > 0 && source.Width > 0 {
+		return source.Height * outWidth / source.Width
+	}
+	return source.Height
+}
+
+// resolveOutput expands the placeholders of the output with the values of the source and the jobs.
+// With a ladder, each rendition gets its own height and job ID, and all of them must be in the same directory
+func (app *App) resolveOutput(source *chunkify.Source) error {
+	template := app.Command.Output
+	if !isOutputTemplate(template) {
+		return nil
+	}
+
+	var outWidth, outHeight int64
+	if width != nil {
+		outWidth = *width
+	}
+	if height != nil {
+		outHeight = *height
+	}
+	// the resolution of a job created by another execution comes from its format
+	if outWidth == 0 && outHeight == 0 && app.Job != nil {
+		outWidth, outHeight = app.Job.Format.Width, app.Job.Format.Height
+	}
+
+	values := outputValues{
+		InputName: inputName(app.Command.Input),
+		Height:    outputHeight(source, outWidth, outHeight),
+		Format:    app.Command.Format,
+		SourceID:  source.ID,
+		Date:      time.Now(),
+		Profile:   app.Command.Profile,
+	}
+	if app.Job != nil {
+		values.JobID = app.Job.ID
+	}
+
+	renditionValues := make([]outputValues, len(app.Command.Renditions))
+	for i, r := range app.Command.Renditions {
+		renditionValues[i] = values
+		renditionValues[i].Height = outputHeight(source, 0, r.Height)
+		renditionValues[i].JobID = r.JobID
+	}
+
+	// the main output is named after the first rendition
+	if len(renditionValues) > 0 {
+		values = renditionValues[0]
+	}
+	app.Command.Output = values.expand(template)
+
+	// renditions are told apart by their suffix if the template doesn't do it,
+	// validateLadderOutput keeps them in the same directory
+	perRendition := strings.Contains(template, "{height}") || strings.Contains(template, "{job_id}")
+
+	for i, r := range app.Command.Renditions {
+		output := renditionOutput(app.Command.Output, r.Name)
+		if perRendition {
+			output = renditionValues[i].expand(template)
+		}
+		app.Command.Renditions[i].Output = output
+	}
+
+	return nil
+}
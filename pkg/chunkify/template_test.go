@@ -0,0 +1,158 @@
+package chunkify
+
+import (
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestValidateOutputTemplate(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "video.mp4"},
+		{output: "out/{input_name}_{height}p_{format}.{ext}"},
+		{output: "{date}/{profile}/{source_id}/{job_id}.mp4"},
+		{output: "{input}.mp4", wantErr: true},
+		{output: "{Height}.mp4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if err := validateOutputTemplate(tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("validateOutputTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutputValuesExpand(t *testing.T) {
+	values := outputValues{
+		InputName: "video",
+		Height:    720,
+		Format:    FormatHlsH264,
+		JobID:     "job_1",
+		SourceID:  "src_1",
+		Date:      time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"out/{input_name}_{height}p_{format}.{ext}", "out/video_720p_hls_h264.m3u8"},
+		{"{date}/{profile}/{source_id}_{job_id}.mp4", "2024-01-31/default/src_1_job_1.mp4"},
+		{"video.mp4", "video.mp4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := values.expand(tt.template); got != tt.want {
+				t.Errorf("expand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputHeight(t *testing.T) {
+	source := &chunkify.Source{Width: 1920, Height: 1080}
+
+	tests := []struct {
+		name          string
+		width, height int64
+		want          int64
+	}{
+		{"height set", 1280, 720, 720},
+		{"width only", 1280, 0, 720},
+		{"not set", 0, 0, 1080},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outputHeight(source, tt.width, tt.height); got != tt.want {
+				t.Errorf("outputHeight() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveOutput(t *testing.T) {
+	resetGlobalFlags()
+	source := &chunkify.Source{ID: "src_1", Width: 1920, Height: 1080}
+
+	t.Run("single job", func(t *testing.T) {
+		app := NewApp()
+		app.Command = &ChunkifyCommand{Input: "in/movie.mov", Output: "out/{input_name}_{height}p/{job_id}.{ext}", Format: FormatMp4H264, Profile: "prod"}
+		app.Job = &chunkify.Job{ID: "job_1"}
+
+		if err := app.resolveOutput(source); err != nil {
+			t.Fatalf("resolveOutput() error = %v", err)
+		}
+		if app.Command.Output != "out/movie_1080p/job_1.mp4" {
+			t.Errorf("unexpected output: %s", app.Command.Output)
+		}
+	})
+
+	t.Run("ladder with height", func(t *testing.T) {
+		app := NewApp()
+		app.Command = &ChunkifyCommand{
+			Input:  "movie.mov",
+			Output: "hls/{input_name}_{height}p.m3u8",
+			Format: FormatHlsH264,
+			Renditions: []Rendition{
+				{Name: "720p", Height: 720, JobID: "job_1"},
+				{Name: "480p", Height: 480, JobID: "job_2"},
+			},
+		}
+
+		if err := app.resolveOutput(source); err != nil {
+			t.Fatalf("resolveOutput() error = %v", err)
+		}
+		if app.Command.Output != "hls/movie_720p.m3u8" {
+			t.Errorf("unexpected output: %s", app.Command.Output)
+		}
+		if app.Command.Renditions[0].Output != "hls/movie_720p.m3u8" || app.Command.Renditions[1].Output != "hls/movie_480p.m3u8" {
+			t.Errorf("unexpected rendition outputs: %+v", app.Command.Renditions)
+		}
+	})
+
+	t.Run("ladder without height", func(t *testing.T) {
+		app := NewApp()
+		app.Command = &ChunkifyCommand{
+			Input:      "movie.mov",
+			Output:     "{date}/{input_name}.m3u8",
+			Format:     FormatHlsH264,
+			Renditions: []Rendition{{Name: "720p", Height: 720}, {Name: "480p", Height: 480}},
+		}
+
+		if err := app.resolveOutput(source); err != nil {
+			t.Fatalf("resolveOutput() error = %v", err)
+		}
+		date := time.Now().Format(time.DateOnly)
+		if app.Command.Renditions[1].Output != date+"/movie_480p.m3u8" {
+			t.Errorf("unexpected rendition output: %s", app.Command.Renditions[1].Output)
+		}
+	})
+}
+
+func TestValidateLadderOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "hls/{input_name}_{height}p.m3u8"},
+		{output: "{date}/{input_name}_{job_id}.m3u8"},
+		{output: "hls/{height}/{input_name}.m3u8", wantErr: true},
+		{output: "{job_id}/video.m3u8", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if err := validateLadderOutput(tt.output); (err != nil) != tt.wantErr {
+				t.Errorf("validateLadderOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -31,6 +31,7 @@ const (
 	Completed
 	Failed
 	Cancelled
+	Detached
 )
 
 var (
@@ -53,6 +54,7 @@ type App struct {
 	Status           int
 	Progress         *Progress
 	Job              *chunkify.Job
+	Jobs             []*chunkify.Job
 	Source           *chunkify.Source
 	Files            []chunkify.APIFile
 	Transcoders      []chunkify.JobTranscoderListResponseData
@@ -61,6 +63,8 @@ type App struct {
 	DownloadedFiles  map[string]chunkify.APIFile
 	Error            error
 	Done             bool
+	Interrupting     bool // the user is asked what to do with the running jobs
+	Cancelling       bool // the running jobs are being cancelled on Chunkify
 	Ctx              context.Context
 	CancelFunc       context.CancelFunc
 
@@ -82,8 +86,8 @@ func NewApp() *App {
 type Progress struct {
 	Status           chan int
 	JobProgress      chan chunkify.Job
+	LadderProgress   chan []*chunkify.Job
 	JobTranscoders   chan []chunkify.JobTranscoderListResponseData
-	JobCompleted     chan bool
 	UploadProgress   chan UploadProgress
 	DownloadProgress chan DownloadProgress
 	Files            chan []chunkify.APIFile
@@ -96,8 +100,8 @@ func NewProgress() *Progress {
 	return &Progress{
 		Status:           make(chan int, 1),
 		JobProgress:      make(chan chunkify.Job, 100),
+		LadderProgress:   make(chan []*chunkify.Job, 100),
 		JobTranscoders:   make(chan []chunkify.JobTranscoderListResponseData, 100),
-		JobCompleted:     make(chan bool, 1),
 		UploadProgress:   make(chan UploadProgress, 100),
 		DownloadProgress: make(chan DownloadProgress, 100),
 		DownloadedFiles:  make(chan chunkify.APIFile, 100),
@@ -112,18 +116,29 @@ func (t App) Init() tea.Cmd {
 	return tea.Batch(tickCmd(), spin.Tick)
 }
 
-func (t App) Run() {
+// Run starts the TUI and blocks until it exits.
+// It returns the error that made the workflow fail, if any
+func (t App) Run() error {
+	// The signals are handled by the app to cancel or detach the running jobs
 	var p *tea.Program
 	if t.JSON {
 		// Disable Bubble Tea renderer in JSON mode to avoid whitespace artifacts
-		p = tea.NewProgram(t, tea.WithoutRenderer())
+		p = tea.NewProgram(t, tea.WithoutRenderer(), tea.WithoutSignalHandler())
 	} else {
-		p = tea.NewProgram(t)
+		p = tea.NewProgram(t, tea.WithoutSignalHandler())
 	}
-	if _, err := p.Run(); err != nil {
+	stop := notifyInterrupts(p)
+	m, err := p.Run()
+	stop()
+	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
+
+	if final, ok := m.(App); ok {
+		return final.Error
+	}
+	return nil
 }
 
 // tickMsg represents a tick event for periodic updates
@@ -139,21 +154,31 @@ func tickCmd() tea.Cmd {
 func (t App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if t.Interrupting {
+			return t.answerInterrupt(msg.String())
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
-			fmt.Println("\nQuitting...")
-			t.CancelFunc()
-			// Send JobCompleted to unblock the main goroutine
-			select {
-			case t.Progress.JobCompleted <- true:
-			default:
-			}
-			return t, tea.Quit
+			return t.interrupt(true)
 		}
+	case interruptMsg:
+		return t.interrupt(false)
+	case jobsCancelledMsg:
+		return t.jobsCancelled(msg.err)
 	case tickMsg:
+		// The workflow is stopped while the jobs are cancelled
+		if t.Cancelling {
+			return t, nil
+		}
+
 		// Check for updates from channels (non-blocking)
 		t, shouldQuit := t.checkChannels()
 
+		// Nothing to ask anymore once the jobs are done
+		if t.Interrupting && len(t.runningJobs()) == 0 {
+			t.Interrupting = false
+		}
+
 		// if JSON mode is enabled, print the JSON to the terminal every second
 		if t.JSON {
 			if shouldQuit || t.Done || time.Since(t.LastJSONOutput) >= time.Second {
@@ -185,6 +210,11 @@ func (t App) checkChannels() (App, bool) {
 		if ok {
 			t.Job = &job
 		}
+	case jobs, ok := <-t.Progress.LadderProgress:
+		if ok && len(jobs) > 0 {
+			t.Jobs = jobs
+			t.Job = jobs[0]
+		}
 	case transcoders, ok := <-t.Progress.JobTranscoders:
 		if ok {
 			t.Transcoders = transcoders
@@ -208,7 +238,8 @@ func (t App) checkChannels() (App, bool) {
 	case status, ok := <-t.Progress.Status:
 		if ok {
 			t.Status = status
-			if status == Completed || status == Failed || status == Cancelled {
+			// on failure, we wait for the error to display it before quitting
+			if status == Completed || status == Cancelled {
 				t.Done = true
 			}
 		}
@@ -218,7 +249,7 @@ func (t App) checkChannels() (App, bool) {
 		}
 	case err := <-t.Progress.Error:
 		t.Error = err
-		t.Progress.JobCompleted <- true
+		t.Done = true
 	case <-t.Ctx.Done():
 		t.Done = true
 	default:
@@ -234,6 +265,8 @@ type JSONOutput struct {
 	Speed    string  `json:"speed"`
 	OutTime  int64   `json:"out_time"`
 	Eta      string  `json:"eta"`
+	// Where the outputs are stored once completed, with --no-download
+	Files []FileLocation `json:"files,omitempty"`
 }
 
 func (t App) JSONView() string {
@@ -255,9 +288,9 @@ func (t App) JSONView() string {
 		speedStr = "N/A"
 		eta = formatter.Duration(int64(t.UploadProgress.Eta.Round(time.Second).Seconds()))
 	case Downloading:
-		progress = t.DownloadProgress.Progress
-		speedStr = formatter.Bitrate(int64(t.DownloadProgress.Speed))
-		eta = formatter.Duration(int64(t.DownloadProgress.Eta.Round(time.Second).Seconds()))
+		progress = t.DownloadProgress.Overall.Progress
+		speedStr = formatter.Bitrate(int64(t.DownloadProgress.Overall.Speed))
+		eta = formatter.Duration(int64(t.DownloadProgress.Overall.Eta.Round(time.Second).Seconds()))
 	case Transcoding:
 		eta = "N/A"
 		if t.Job != nil {
@@ -270,6 +303,15 @@ func (t App) JSONView() string {
 
 			speedStr = fmt.Sprintf("%.1fx", speed)
 			progress = t.Job.Progress
+
+			// with a ladder, the progress is the average of all renditions
+			if len(t.Jobs) > 0 {
+				progress = 0
+				for _, job := range t.Jobs {
+					progress += job.Progress
+				}
+				progress = progress / float64(len(t.Jobs))
+			}
 		}
 	}
 
@@ -281,6 +323,9 @@ func (t App) JSONView() string {
 		OutTime:  outTime,
 		Eta:      eta,
 	}
+	if t.Command.NoDownload && t.Status == Completed {
+		out.Files = fileLocations(t.Files)
+	}
 	j, _ := json.Marshal(out)
 	return string(j)
 }
@@ -305,12 +350,16 @@ func (t App) View() string {
 	// Display job progress
 	if t.Command.Format != "" && t.Status >= Transcoding && t.Job != nil {
 		view += "\n"
-		v, statusInfo = t.transcodingView()
+		if len(t.Jobs) > 0 {
+			v, statusInfo = t.ladderView()
+		} else {
+			v, statusInfo = t.transcodingView()
+		}
 		view += v
 	}
 
 	// Display download progress
-	if t.Command.Output != "" && t.Job != nil && t.Job.Status == chunkify.JobStatusCompleted && t.Status >= Downloading {
+	if t.Command.Output != "" && !t.Command.NoDownload && t.Job != nil && t.jobsCompleted() && t.Status >= Downloading {
 		v, statusInfo = t.downloadView()
 		view += v
 	}
@@ -327,6 +376,10 @@ func (t App) View() string {
 		view += t.summaryView()
 	}
 
+	if t.Interrupting {
+		view += t.interruptView()
+	}
+
 	return view
 }
 
@@ -445,6 +498,46 @@ func (t App) transcodingView() (string, string) {
 	return view, statusInfo
 }
 
+// ladderView displays one progress bar per rendition of the HLS ladder
+func (t App) ladderView() (string, string) {
+	view := ""
+	progress := 0.0
+
+	if t.jobsCompleted() {
+		view += fmt.Sprintf("%s%s Transcoding completed\n", indent, completedIcon.String())
+	} else {
+		view += currentStepText(fmt.Sprintf("%s%s Transcoding (%d renditions)", indent, spin.View(), len(t.Jobs)))
+		view += " " + formatter.TimeDiff(t.Jobs[0].CreatedAt, time.Now())
+		view += "\n"
+	}
+
+	for i, job := range t.Jobs {
+		name := job.ID
+		if i < len(t.Command.Renditions) {
+			name = t.Command.Renditions[i].Name
+		}
+		view += fmt.Sprintf("%s%-6s %s %.f%% %s\n", dblIndent, name, progressBar(string(job.Status), job.Progress, 30), job.Progress, grayText(string(job.Status)))
+		progress += job.Progress
+	}
+
+	statusInfo := statusOrangeText(t.Command.Format)
+	statusInfo += fmt.Sprintf(" %.f%%", progress/float64(len(t.Jobs)))
+	return view, statusInfo
+}
+
+// jobsCompleted returns true when the job, or all the jobs of the ladder, are completed
+func (t App) jobsCompleted() bool {
+	if len(t.Jobs) == 0 {
+		return t.Job != nil && t.Job.Status == chunkify.JobStatusCompleted
+	}
+	for _, job := range t.Jobs {
+		if job.Status != chunkify.JobStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
 func (t App) downloadView() (string, string) {
 	view := ""
 	statusInfo := ""
@@ -454,7 +547,8 @@ func (t App) downloadView() (string, string) {
 	if t.Status == Completed && len(t.DownloadedFiles) == len(t.Files) {
 		view += fmt.Sprintf("%s%s All files saved\n", indent, completedIcon.String())
 	} else {
-		statusInfo = fmt.Sprintf("%.1f%% (%.1f MB/s, ETA: %s)", t.DownloadProgress.Progress, t.DownloadProgress.Speed/(1024*1024), t.DownloadProgress.Eta.Round(time.Second))
+		overall := t.DownloadProgress.Overall
+		statusInfo = fmt.Sprintf("%.1f%% %s/%s (%.1f MB/s, ETA: %s)", overall.Progress, formatter.Size(overall.WrittenBytes), formatter.Size(overall.TotalBytes), overall.Speed/(1024*1024), overall.Eta.Round(time.Second))
 		view += currentStepText(fmt.Sprintf("%s%s Saving files",
 			indent,
 			spin.View(),
@@ -475,7 +569,7 @@ func (t App) downloadView() (string, string) {
 			currentFile = 0
 		}
 		if len(t.Files) <= 10 {
-			info := fmt.Sprintf("%s (%s)", filename(file, t.Command.Output), formatter.Size(file.Size))
+			info := fmt.Sprintf("%s (%s)", t.Command.OutputFor(file), formatter.Size(file.Size))
 			if downloaded {
 				view += dblIndent + "- " + info
 			} else {
@@ -486,7 +580,7 @@ func (t App) downloadView() (string, string) {
 	}
 
 	if len(t.Files) > 10 {
-		view += fmt.Sprintf("%s%d/%d %s", dblIndent, len(t.DownloadedFiles), len(t.Files), filename(t.Files[currentFile], t.Command.Output))
+		view += fmt.Sprintf("%s%d/%d %s", dblIndent, len(t.DownloadedFiles), len(t.Files), t.Command.OutputFor(t.Files[currentFile]))
 		view += "\n"
 	}
 
@@ -534,6 +628,22 @@ func (t App) summaryView() string {
 		return view
 	}
 
+	if len(t.Jobs) > 0 {
+		view += fmt.Sprintf("%sHLS Manifest: %s\n", indent, t.Jobs[0].HlsManifestID)
+		view += fmt.Sprintf("%sSource ID: %s\n", indent, t.Jobs[0].SourceID)
+		view += fmt.Sprintf("%sFormat: %s\n", indent, t.Command.Format)
+		for i, job := range t.Jobs {
+			name := ""
+			if i < len(t.Command.Renditions) {
+				name = t.Command.Renditions[i].Name
+			}
+			view += fmt.Sprintf("%s- %s Job ID: %s Billable time: %ds\n", indent, name, job.ID, job.BillableTime)
+		}
+		view += t.storageView()
+		view += "\n"
+		return view
+	}
+
 	if t.Job != nil {
 		view += fmt.Sprintf("%sJob ID: %s\n", indent, t.Job.ID)
 		view += fmt.Sprintf("%sSource ID: %s\n", indent, t.Job.SourceID)
@@ -550,14 +660,49 @@ func (t App) summaryView() string {
 		view += fmt.Sprintf("%sSpeed: %.1fx\n", indent, speed)
 		view += fmt.Sprintf("%sTranscoding time: %s\n", indent, formatter.TimeDiff(t.Job.StartedAt, t.Job.UpdatedAt))
 		view += fmt.Sprintf("%sBillable time: %ds\n", indent, t.Job.BillableTime)
+
+		if t.Status == Detached {
+			view += fmt.Sprintf("\n%sThe job keeps running on Chunkify. %s\n", indent, watchHint(t.Job.ID, t.Command.Output))
+		}
+
+		view += t.storageView()
 	}
 
 	view += "\n"
 	return view
 }
 
+// storageView lists where the outputs are stored when they are not downloaded
+func (t App) storageView() string {
+	if !t.Command.NoDownload || t.Status != Completed || len(t.Files) == 0 {
+		return ""
+	}
+
+	view := fmt.Sprintf("\n%sFiles:\n", indent)
+	for _, file := range t.Files {
+		view += fmt.Sprintf("%s- %s (%s)\n", indent, fileLocation(file), formatter.Size(file.Size))
+		if file.URL != "" {
+			view += fmt.Sprintf("%s  %s\n", indent, grayText(file.URL))
+		}
+	}
+	return view
+}
+
+func (t App) interruptView() string {
+	view := fmt.Sprintf("\n%sThe job is still running on Chunkify:\n", indent)
+	if len(t.Jobs) > 1 {
+		view = fmt.Sprintf("\n%sThe jobs are still running on Chunkify:\n", indent)
+	}
+	view += fmt.Sprintf("%s%s cancel the job  %s detach and exit  %s keep waiting\n", indent, currentStepText("[c]"), currentStepText("[d]"), currentStepText("[w]"))
+	return view
+}
+
 // getStatusString returns a human-readable status string
 func (t App) getStatusString() string {
+	if t.Cancelling {
+		return "Cancelling"
+	}
+
 	switch t.Status {
 	case Status:
 		return "Initializing"
@@ -580,6 +725,8 @@ func (t App) getStatusString() string {
 		return "Failed"
 	case Cancelled:
 		return "Cancelled"
+	case Detached:
+		return "Detached"
 	default:
 		return "Unknown"
 	}
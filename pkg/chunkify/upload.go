@@ -2,8 +2,10 @@ package chunkify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -31,7 +33,7 @@ func UploadBlob(ctx context.Context, r io.Reader, uploadResponse *chunkify.Uploa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to upload file: %s", resp.Status)
+		return &uploadStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	return nil
@@ -101,15 +103,48 @@ type UploadProgress struct {
 	Eta time.Duration
 }
 
+// UploadRetries is the number of times a failed upload is retried
+// UploadRetryBackoff is the delay before the first retry, doubled after each attempt
+var (
+	UploadRetries      = 3
+	UploadRetryBackoff = 2 * time.Second
+)
+
+// uploadStatusError is an upload answered with an unexpected status code
+type uploadStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *uploadStatusError) Error() string {
+	return fmt.Sprintf("failed to upload file: %s", e.Status)
+}
+
+// isPermanentUploadError reports whether retrying the upload can't help
+func isPermanentUploadError(err error) bool {
+	var statusErr *uploadStatusError
+	return errors.As(err, &statusErr) && isPermanentStatus(statusErr.StatusCode)
+}
+
 // UploadBlobWithProgress uploads a file to the specified URL and sends the progress to the progress channel.
 // UploadCreate must be called first to get the URL of the upload.
-// The r parameter is the reader of the file to upload.
+// The r parameter is the reader of the file to upload. If it's an io.Seeker,
+// failed uploads are retried from the start with exponential backoff.
+// Client errors other than 408 and 429 are not retried.
 // Returns an error if the request fails.
 func UploadBlobWithProgress(ctx context.Context, r io.Reader, uploadResponse *chunkify.Upload, progress chan UploadProgress) error {
+	defer func() {
+		// Ensure channel is closed by the producer when finished
+		close(progress)
+	}()
+
 	// Determine total size if possible
 	var size int64 = -1
-	if seeker, ok := r.(io.Seeker); ok {
+	var start int64
+	seeker, canRetry := r.(io.Seeker)
+	if canRetry {
 		if curr, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			start = curr
 			if end, err2 := seeker.Seek(0, io.SeekEnd); err2 == nil {
 				size = end - curr
 				_, _ = seeker.Seek(curr, io.SeekStart)
@@ -117,12 +152,49 @@ func UploadBlobWithProgress(ctx context.Context, r io.Reader, uploadResponse *ch
 		}
 	}
 
+	backoff := UploadRetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= UploadRetries; attempt++ {
+		if attempt > 0 {
+			if !canRetry {
+				break
+			}
+
+			slog.Info("Retrying upload", "upload", uploadResponse.ID, "attempt", attempt, "backoff", backoff, "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+
+			if _, serr := seeker.Seek(start, io.SeekStart); serr != nil {
+				return err
+			}
+		}
+
+		if err = putBlob(ctx, r, uploadResponse.UploadURL, size, progress); err == nil {
+			return nil
+		}
+
+		// no need to retry if the upload was cancelled or refused
+		if ctx.Err() != nil || isPermanentUploadError(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// putBlob sends the whole reader in a single PUT request and reports the progress
+func putBlob(ctx context.Context, r io.Reader, url string, size int64, progress chan UploadProgress) error {
 	// Wrap reader to capture progress
 	pw := &uploadProgressWriter{ch: progress, total: size, startTime: time.Now()}
 	body := io.TeeReader(r, pw)
 
 	// http put request with reader
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadResponse.UploadURL, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, body)
 	if err != nil {
 		return err
 	}
@@ -143,11 +215,6 @@ func UploadBlobWithProgress(ctx context.Context, r io.Reader, uploadResponse *ch
 	default:
 	}
 
-	defer func() {
-		// Ensure channel is closed by the producer when finished
-		close(progress)
-	}()
-
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
@@ -156,7 +223,7 @@ func UploadBlobWithProgress(ctx context.Context, r io.Reader, uploadResponse *ch
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to upload file: %s", resp.Status)
+		return &uploadStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	// Ensure final 100% progress if total was known
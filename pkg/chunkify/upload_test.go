@@ -0,0 +1,118 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestUploadBlobWithProgress_Retry(t *testing.T) {
+	UploadRetryBackoff = time.Millisecond
+	defer func() { UploadRetryBackoff = 2 * time.Second }()
+
+	testData := []byte("Hello, World! This upload fails once.")
+	requests := 0
+	var received []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	progress := make(chan UploadProgress, 100)
+	upload := &chunkify.Upload{ID: "upl_123", UploadURL: server.URL}
+
+	if err := UploadBlobWithProgress(context.Background(), bytes.NewReader(testData), upload, progress); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if string(received) != string(testData) {
+		t.Errorf("Expected the whole file to be sent again, got %q", received)
+	}
+
+	// the channel is closed once the upload is done
+	var last UploadProgress
+	for p := range progress {
+		last = p
+	}
+	if last.Progress != 100 {
+		t.Errorf("Expected final progress 100, got %f", last.Progress)
+	}
+}
+
+func TestUploadBlobWithProgress_NoRetryWithoutSeeker(t *testing.T) {
+	UploadRetryBackoff = time.Millisecond
+	defer func() { UploadRetryBackoff = 2 * time.Second }()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	progress := make(chan UploadProgress, 100)
+	upload := &chunkify.Upload{ID: "upl_123", UploadURL: server.URL}
+
+	// a reader that can't be rewound can only be sent once
+	r := io.MultiReader(bytes.NewReader([]byte("data")))
+	if err := UploadBlobWithProgress(context.Background(), r, upload, progress); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestUploadBlobWithProgress_ClientErrors(t *testing.T) {
+	UploadRetryBackoff = time.Millisecond
+	defer func() { UploadRetryBackoff = 2 * time.Second }()
+
+	tests := []struct {
+		status   int
+		requests int
+	}{
+		// an expired pre-signed URL can't succeed when retried
+		{http.StatusForbidden, 1},
+		{http.StatusRequestTimeout, UploadRetries + 1},
+		{http.StatusTooManyRequests, UploadRetries + 1},
+	}
+
+	for _, tt := range tests {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			io.ReadAll(r.Body)
+			w.WriteHeader(tt.status)
+		}))
+
+		progress := make(chan UploadProgress, 100)
+		upload := &chunkify.Upload{ID: "upl_123", UploadURL: server.URL}
+		if err := UploadBlobWithProgress(context.Background(), bytes.NewReader([]byte("data")), upload, progress); err == nil {
+			t.Errorf("%d: expected error", tt.status)
+		}
+		server.Close()
+
+		if requests != tt.requests {
+			t.Errorf("%d: expected %d requests, got %d", tt.status, tt.requests, requests)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+var uploadStatusFilters = []string{
+	string(chunkify.UploadListParamsStatusWaiting),
+	string(chunkify.UploadListParamsStatusCompleted),
+	string(chunkify.UploadListParamsStatusFailed),
+	string(chunkify.UploadListParamsStatusExpired),
+}
+
+// UploadResult is the source created from an uploaded file
+type UploadResult struct {
+	Path      string `json:"path"`
+	SourceID  string `json:"source_id,omitempty"`
+	Cancelled bool   `json:"cancelled,omitempty"` // the uploads were interrupted before the file was started
+	Error     string `json:"error,omitempty"`
+	Err       error  `json:"-"`
+}
+
+// UploadListFilters holds the filters of the uploads list command
+type UploadListFilters struct {
+	Status        string
+	Metadata      []string // key=value pairs the uploads metadata must contain
+	CreatedAfter  string   // date, RFC3339 time or duration ago
+	CreatedBefore string   // date, RFC3339 time or duration ago
+	Limit         int64    // maximum number of uploads listed, 0 lists all
+}
+
+func NewUploadCommand(cfg *config.Config) *Command {
+	app := NewApp()
+	app.Command = &ChunkifyCommand{}
+
+	var (
+		recursive   bool
+		concurrency int
+	)
+
+	cmd := &Command{
+		App:    app,
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "upload <files...>",
+			Short: "Upload videos and create their sources",
+			Long: `Upload videos and create their sources
+
+Files can be given as paths, globs or directories. Files already uploaded in the last 12 hours are not uploaded again, their source is reused.
+The source ID of each file is printed once all of them are uploaded.
+
+Examples:
+
+chunkify upload video.mp4
+chunkify upload videos/ "raw/*.mov" --concurrency 4
+chunkify upload videos/ -R --json
+`,
+			Args: cobra.MinimumNArgs(1),
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				if concurrency < 1 || concurrency > 32 {
+					return fmt.Errorf("--concurrency must be between 1 and 32")
+				}
+				for _, arg := range args {
+					if isRemoteInput(arg) {
+						return fmt.Errorf("%s is not a file. Use chunkify -i <url> to create a source from a URL", arg)
+					}
+				}
+				return nil
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				app.Client = cfg.Client
+				app.Command.Profile = cfg.Profile
+
+				files, err := expandInputs(args, "", recursive)
+				if err != nil {
+					fmt.Printf("Error: %s\n", err)
+					os.Exit(1)
+				}
+				if len(files) == 0 {
+					fmt.Println("Error: no video found in the given files")
+					os.Exit(1)
+				}
+
+				results := runUploads(app, files, concurrency)
+				if app.JSON {
+					formatter.PrintJSON(os.Stdout, results)
+				} else {
+					fmt.Println()
+					printUploadResults(os.Stdout, results)
+				}
+
+				for _, r := range results {
+					if r.Err != nil {
+						os.Exit(1)
+					}
+				}
+			},
+		},
+	}
+
+	cmd.Command.Flags().BoolVarP(&recursive, "recursive", "R", false, "Look for videos in subdirectories of directory inputs")
+	cmd.Command.Flags().IntVar(&concurrency, "concurrency", 2, "Number of files uploaded at the same time (1-32)")
+	cmd.Command.Flags().BoolVar(&app.JSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func NewUploadsCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "uploads",
+			Short: "List the uploads",
+			Long: `List the uploads of the project, including the pending, failed and expired ones
+
+Examples:
+
+chunkify uploads list --status waiting
+chunkify uploads list --status failed --created-after 24h
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newUploadsListCommand(cfg))
+
+	return cmd
+}
+
+func newUploadsListCommand(cfg *config.Config) *cobra.Command {
+	filters := UploadListFilters{}
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the uploads, most recent first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params, err := filters.Params()
+			if err != nil {
+				return err
+			}
+
+			uploads, err := ListUploads(cmd.Context(), cfg.Client, params, filters.Limit)
+			if err != nil {
+				return fmt.Errorf("error listing uploads: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, uploads)
+			}
+			return printUploads(os.Stdout, uploads)
+		},
+	}
+
+	cmd.Flags().StringVar(&filters.Status, "status", "", "Only list the uploads with the given status: "+strings.Join(uploadStatusFilters, ", "))
+	cmd.Flags().StringArrayVar(&filters.Metadata, "metadata", nil, "Only list the uploads with the given metadata, as key=value. Can be repeated")
+	cmd.Flags().StringVar(&filters.CreatedAfter, "created-after", "", "Only list the uploads created after the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().StringVar(&filters.CreatedBefore, "created-before", "", "Only list the uploads created before the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().Int64Var(&filters.Limit, "limit", 0, "Maximum number of uploads to list. All the uploads are listed by default")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// runUploads uploads the files and shows the progress of each of them, unless the output is JSON
+func runUploads(template *App, files []string, concurrency int) []UploadResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if template.JSON {
+		return uploadFiles(ctx, template, files, concurrency, nil, nil)
+	}
+
+	p := tea.NewProgram(newUploadsModel(files, cancel))
+	results := make(chan []UploadResult, 1)
+	go func() {
+		results <- uploadFiles(ctx, template, files, concurrency,
+			func(i int, progress UploadProgress) { p.Send(uploadProgressMsg{index: i, progress: progress}) },
+			func(i int, result UploadResult) { p.Send(uploadDoneMsg{index: i, result: result}) })
+		p.Send(uploadsDoneMsg{})
+	}()
+
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Alas, there's been an error: %v", err)
+		os.Exit(1)
+	}
+
+	// stop the uploads if the user quit
+	cancel()
+	return <-results
+}
+
+// uploadFiles uploads the files, concurrency at a time. The results are in the same order as the files.
+// progress is called with the upload progress of each file and done once its source is created
+func uploadFiles(ctx context.Context, template *App, files []string, concurrency int, progress func(int, UploadProgress), done func(int, UploadResult)) []UploadResult {
+	results := make([]UploadResult, len(files))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = uploadFile(ctx, template, files[i], func(pr UploadProgress) {
+					if progress != nil {
+						progress(i, pr)
+					}
+				})
+				if done != nil {
+					done(i, results[i])
+				}
+			}
+		}()
+	}
+
+	for i := range files {
+		// the files left are cancelled once the uploads are interrupted
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case queue <- i:
+				continue
+			}
+		}
+		err := fmt.Errorf("not started, the uploads were interrupted")
+		results[i] = UploadResult{Path: files[i], Cancelled: true, Error: err.Error(), Err: err}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// uploadFile creates the source of a file with the same MD5 dedupe and resume as a transcode
+func uploadFile(ctx context.Context, template *App, file string, progress func(UploadProgress)) UploadResult {
+	app := newPipelineApp(template, file, "")
+
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case pr, ok := <-app.Progress.UploadProgress:
+				// closed once the file is uploaded
+				if !ok {
+					return
+				}
+				progress(pr)
+			case <-app.Progress.Status:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	source, err := app.CreateSource(ctx)
+	close(stop)
+
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return UploadResult{Path: file, Error: err.Error(), Err: err}
+	}
+	return UploadResult{Path: file, SourceID: source.ID}
+}
+
+type uploadProgressMsg struct {
+	index    int
+	progress UploadProgress
+}
+
+type uploadDoneMsg struct {
+	index  int
+	result UploadResult
+}
+
+type uploadsDoneMsg struct{}
+
+// uploadsModel shows the progress of each file of the upload command
+type uploadsModel struct {
+	files    []string
+	progress []UploadProgress
+	results  []*UploadResult
+	cancel   context.CancelFunc
+}
+
+func newUploadsModel(files []string, cancel context.CancelFunc) uploadsModel {
+	return uploadsModel{
+		files:    files,
+		progress: make([]UploadProgress, len(files)),
+		results:  make([]*UploadResult, len(files)),
+		cancel:   cancel,
+	}
+}
+
+func (m uploadsModel) Init() tea.Cmd {
+	spin.Spinner = spinner.MiniDot
+	return spin.Tick
+}
+
+func (m uploadsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			fmt.Println("\nQuitting...")
+			m.cancel()
+			return m, tea.Quit
+		}
+	case uploadProgressMsg:
+		m.progress[msg.index] = msg.progress
+	case uploadDoneMsg:
+		m.results[msg.index] = &msg.result
+	case uploadsDoneMsg:
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		spin, cmd = spin.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m uploadsModel) View() string {
+	view := ""
+	uploaded := 0
+	for i, file := range m.files {
+		result := m.results[i]
+		switch {
+		case result != nil && result.Err != nil:
+			uploaded++
+			view += fmt.Sprintf("%s%s %s: %s\n", indent, errorText("✗"), file, result.Err)
+		case result != nil:
+			uploaded++
+			view += fmt.Sprintf("%s%s %s %s\n", indent, completedIcon, file, grayText(result.SourceID))
+		case m.progress[i].TotalBytes > 0:
+			pr := m.progress[i]
+			view += fmt.Sprintf("%s%s %s %s %.1f%% (%s, ETA: %s)\n", indent, spin.View(), file, progressBar("uploading", pr.Progress, 20), pr.Progress, formatter.Bitrate(int64(pr.Speed)), pr.Eta.Round(time.Second))
+		default:
+			view += grayText(fmt.Sprintf("%s> %s", indent, file)) + "\n"
+		}
+	}
+
+	view += fmt.Sprintf("\n%s%s %d/%d\n", indent, statusText("Uploading"), uploaded, len(m.files))
+	return view
+}
+
+// Params converts the filters to the list params, sorted by creation date, most recent first
+func (f UploadListFilters) Params() (chunkify.UploadListParams, error) {
+	params := chunkify.UploadListParams{
+		Created: chunkify.UploadListParamsCreated{Sort: "desc"},
+	}
+
+	if f.Status != "" {
+		if !slices.Contains(uploadStatusFilters, f.Status) {
+			return params, fmt.Errorf("invalid status: %s. Expected one of %s", f.Status, strings.Join(uploadStatusFilters, ", "))
+		}
+		params.Status = chunkify.UploadListParamsStatus(f.Status)
+	}
+
+	metadata, err := formatter.ParseMetadata(f.Metadata)
+	if err != nil {
+		return params, err
+	}
+	params.Metadata = metadata
+
+	params.Created.Gte, params.Created.Lte, err = formatter.ParseCreated(f.CreatedAfter, f.CreatedBefore, time.Now())
+	if err != nil {
+		return params, err
+	}
+
+	if f.Limit < 0 {
+		return params, fmt.Errorf("--limit must be positive")
+	}
+
+	return params, nil
+}
+
+// ListUploads returns the uploads matching params, fetching the pages until limit is reached.
+// A limit of 0 returns all the uploads
+func ListUploads(ctx context.Context, client *chunkify.Client, params chunkify.UploadListParams, limit int64) ([]chunkify.Upload, error) {
+	return formatter.Paginate(limit, func(offset int64, size int64) ([]chunkify.Upload, int64, error) {
+		params.Limit = chunkify.Int(size)
+		params.Offset = chunkify.Int(offset)
+		page, err := client.Uploads.List(ctx, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		return page.Data, page.Total, nil
+	})
+}
+
+func printUploads(w io.Writer, uploads []chunkify.Upload) error {
+	if len(uploads) == 0 {
+		fmt.Fprintln(w, indent+"No upload found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tCREATED\tSTATUS\tEXPIRES\tFILE\tSOURCE\tERROR")
+	for _, u := range uploads {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			indent,
+			u.ID,
+			u.CreatedAt.Local().Format(time.DateTime),
+			u.Status,
+			u.ExpiresAt.Local().Format(time.DateTime),
+			formatter.ValueOrDash(u.Metadata["file_name"]),
+			formatter.ValueOrDash(u.SourceID),
+			formatter.ValueOrDash(u.Error.Message))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(uploads))
+	return nil
+}
+
+// printUploadResults prints a table with the source ID of each file
+func printUploadResults(w io.Writer, results []UploadResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"FILE\tSOURCE")
+
+	failed, cancelled := 0, 0
+	for _, r := range results {
+		source := r.SourceID
+		if r.Cancelled {
+			cancelled++
+			source = "cancelled: " + r.Err.Error()
+		} else if r.Err != nil {
+			failed++
+			// keep the table readable with multiline errors
+			source = "failed: " + strings.Join(strings.Fields(r.Err.Error()), " ")
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\n", indent, r.Path, source)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%s%d uploaded, %d failed", indent, len(results)-failed-cancelled, failed)
+	if cancelled > 0 {
+		fmt.Fprintf(w, ", %d cancelled", cancelled)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,220 @@
+package chunkify
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chunkifydev/chunkify-go"
+)
+
+func TestUploadFiles(t *testing.T) {
+	setUploadStateDir(t)
+
+	dir := t.TempDir()
+	uploaded := filepath.Join(dir, "uploaded.mp4")
+	failing := filepath.Join(dir, "failing.mp4")
+	os.WriteFile(uploaded, []byte("uploaded"), 0644)
+	os.WriteFile(failing, []byte("failing"), 0644)
+
+	sum := md5.Sum([]byte("uploaded"))
+	uploadedMd5 := hex.EncodeToString(sum[:])
+
+	var mu sync.Mutex
+	var uploadBody string
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/sources":
+			fmt.Fprintf(w, `{"data":[{"id":"src_1","created_at":%q,"metadata":{"md5":%q}}],"total":1,"offset":0}`, time.Now().Format(time.RFC3339), uploadedMd5)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			uploadBody = string(body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"upload failed"}}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	template := NewApp()
+	template.Client = client
+	template.Command = &ChunkifyCommand{}
+
+	done := 0
+	results := uploadFiles(context.Background(), template, []string{uploaded, failing}, 2, nil, func(int, UploadResult) {
+		mu.Lock()
+		done++
+		mu.Unlock()
+	})
+
+	if done != 2 {
+		t.Errorf("expected done to be called twice, got %d", done)
+	}
+	if results[0].Path != uploaded || results[0].SourceID != "src_1" || results[0].Err != nil {
+		t.Errorf("expected the uploaded file to reuse src_1, got %+v", results[0])
+	}
+	if results[1].Path != failing || results[1].SourceID != "" || results[1].Err == nil {
+		t.Errorf("expected the failing file to fail, got %+v", results[1])
+	}
+	if !strings.Contains(uploadBody, `"file_name":"failing.mp4"`) {
+		t.Errorf("expected the file name in the upload metadata, got %s", uploadBody)
+	}
+
+	var out bytes.Buffer
+	printUploadResults(&out, results)
+	if !strings.Contains(out.String(), "src_1") || !strings.Contains(out.String(), "1 uploaded, 1 failed") {
+		t.Errorf("unexpected results:\n%s", out.String())
+	}
+
+	data, _ := json.Marshal(results)
+	if !strings.Contains(string(data), `"path":"`+strings.ReplaceAll(uploaded, `\`, `\\`)+`","source_id":"src_1"`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestListUploads_Pagination(t *testing.T) {
+	total := 130
+	requests := 0
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		uploads := []map[string]string{}
+		for i := offset; i < min(offset+limit, total); i++ {
+			uploads = append(uploads, map[string]string{"id": fmt.Sprintf("upl_%d", i)})
+		}
+		data, _ := json.Marshal(uploads)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":%s,"total":%d,"offset":%d}`, data, total, offset)
+	})
+
+	uploads, err := ListUploads(context.Background(), client, chunkify.UploadListParams{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != total || requests != 2 {
+		t.Errorf("expected %d uploads in 2 requests, got %d uploads in %d requests", total, len(uploads), requests)
+	}
+
+	requests = 0
+	uploads, err = ListUploads(context.Background(), client, chunkify.UploadListParams{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 10 || requests != 1 {
+		t.Errorf("expected 10 uploads in 1 request, got %d uploads in %d requests", len(uploads), requests)
+	}
+}
+
+func TestUploadListFilters_Params(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters UploadListFilters
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			filters: UploadListFilters{Status: "expired", Metadata: []string{"origin=chunkify/cli"}, CreatedAfter: "24h", CreatedBefore: "2025-01-02"},
+		},
+		{
+			name:    "invalid status",
+			filters: UploadListFilters{Status: "pending"},
+			wantErr: "invalid status",
+		},
+		{
+			name:    "invalid metadata",
+			filters: UploadListFilters{Metadata: []string{"origin"}},
+			wantErr: "invalid metadata filter",
+		},
+		{
+			name:    "invalid date",
+			filters: UploadListFilters{CreatedAfter: "yesterday"},
+			wantErr: "invalid --created-after",
+		},
+		{
+			name:    "negative limit",
+			filters: UploadListFilters{Limit: -1},
+			wantErr: "--limit must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := tt.filters.Params()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.Status != chunkify.UploadListParamsStatusExpired || params.Created.Gte.Value == 0 || params.Created.Lte.Value == 0 {
+				t.Errorf("unexpected params: %+v", params)
+			}
+		})
+	}
+}
+
+func TestPrintUploads(t *testing.T) {
+	uploads := []chunkify.Upload{
+		{ID: "upl_1", Status: chunkify.UploadStatusWaiting, Metadata: map[string]string{"file_name": "video.mp4"}},
+		{ID: "upl_2", Status: chunkify.UploadStatusCompleted, SourceID: "src_1"},
+	}
+
+	var out bytes.Buffer
+	if err := printUploads(&out, uploads); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"upl_1", "waiting", "video.mp4", "src_1", "Total: 2"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printUploads(&out, nil)
+	if !strings.Contains(out.String(), "No upload found") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestUploadFiles_Interrupted(t *testing.T) {
+	template := NewApp()
+	template.Command = &ChunkifyCommand{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := uploadFiles(ctx, template, []string{"a.mp4", "b.mp4"}, 1, nil, nil)
+
+	for i, r := range results {
+		if !r.Cancelled || r.Err == nil {
+			t.Errorf("result %d: expected the file to be cancelled, got %+v", i, r)
+		}
+	}
+
+	var out bytes.Buffer
+	printUploadResults(&out, results)
+	if !strings.Contains(out.String(), "0 uploaded, 0 failed, 2 cancelled") {
+		t.Errorf("unexpected results:\n%s", out.String())
+	}
+}
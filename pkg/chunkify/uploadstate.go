@@ -0,0 +1,100 @@
+package chunkify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UploadState is saved locally while a file is being uploaded, keyed by the file MD5.
+// If the CLI is interrupted, the next run with the same file picks up the same upload
+// instead of creating a new one.
+type UploadState struct {
+	Md5            string    `json:"md5"`
+	UploadID       string    `json:"upload_id"`
+	CliExecutionID string    `json:"cli_execution_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// uploadStateDir can be overridden in tests
+var uploadStateDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "chunkify", "uploads"), nil
+}
+
+func uploadStatePath(md5 string) (string, error) {
+	dir, err := uploadStateDir()
+	if err != nil {
+		return "", fmt.Errorf("upload state dir: %w", err)
+	}
+	return filepath.Join(dir, md5+".json"), nil
+}
+
+// LoadUploadState returns the saved state of the upload of the file with the given MD5.
+// It returns nil if there is none or if the upload has expired.
+func LoadUploadState(md5 string) (*UploadState, error) {
+	statePath, err := uploadStatePath(md5)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read upload state: %w", err)
+	}
+
+	state := &UploadState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode upload state: %w", err)
+	}
+
+	if !state.ExpiresAt.IsZero() && state.ExpiresAt.Before(time.Now()) {
+		DeleteUploadState(md5)
+		return nil, nil
+	}
+
+	return state, nil
+}
+
+// SaveUploadState persists the upload state
+func SaveUploadState(state UploadState) error {
+	statePath, err := uploadStatePath(state.Md5)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return fmt.Errorf("create upload state dir: %w", err)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode upload state: %w", err)
+	}
+
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		return fmt.Errorf("write upload state: %w", err)
+	}
+	return nil
+}
+
+// DeleteUploadState removes the upload state once the source is created
+func DeleteUploadState(md5 string) error {
+	statePath, err := uploadStatePath(md5)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete upload state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package chunkify
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setUploadStateDir(t *testing.T) {
+	dir := t.TempDir()
+	previous := uploadStateDir
+	uploadStateDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { uploadStateDir = previous })
+}
+
+func TestUploadState_SaveLoadDelete(t *testing.T) {
+	setUploadStateDir(t)
+
+	state := UploadState{
+		Md5:            "d41d8cd98f00b204e9800998ecf8427e",
+		UploadID:       "upl_123",
+		CliExecutionID: "exec_123",
+		ExpiresAt:      time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+
+	if err := SaveUploadState(state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := LoadUploadState(state.Md5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("Expected upload state to be loaded")
+	}
+	if loaded.UploadID != state.UploadID || loaded.CliExecutionID != state.CliExecutionID || !loaded.ExpiresAt.Equal(state.ExpiresAt) {
+		t.Errorf("Expected %+v, got %+v", state, *loaded)
+	}
+
+	if err := DeleteUploadState(state.Md5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err = LoadUploadState(state.Md5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded != nil {
+		t.Errorf("Expected no upload state after delete, got %+v", *loaded)
+	}
+
+	// deleting twice is not an error
+	if err := DeleteUploadState(state.Md5); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestUploadState_Expired(t *testing.T) {
+	setUploadStateDir(t)
+
+	state := UploadState{
+		Md5:       "md5_expired",
+		UploadID:  "upl_123",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	if err := SaveUploadState(state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := LoadUploadState(state.Md5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded != nil {
+		t.Errorf("Expected expired upload state to be ignored, got %+v", *loaded)
+	}
+}
+
+func TestCreateSourceFromFile_CompletedUpload(t *testing.T) {
+	setUploadStateDir(t)
+
+	input := filepath.Join(t.TempDir(), "video.mp4")
+	os.WriteFile(input, []byte("video"), 0644)
+	sum := md5.Sum([]byte("video"))
+	videoMd5 := hex.EncodeToString(sum[:])
+
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/sources":
+			// no source with the MD5 yet
+			fmt.Fprint(w, `{"data":[],"total":0,"offset":0}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/uploads/upl_1":
+			fmt.Fprint(w, `{"data":{"id":"upl_1","status":"completed","source_id":"src_1"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/sources/src_1":
+			fmt.Fprint(w, `{"data":{"id":"src_1"}}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	app := NewApp()
+	app.Client = client
+	app.Command = &ChunkifyCommand{Id: "exec_2", Input: input}
+
+	if err := SaveUploadState(UploadState{Md5: videoMd5, UploadID: "upl_1", CliExecutionID: "exec_1", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := app.CreateSourceFromFile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.ID != "src_1" {
+		t.Errorf("expected the source of the completed upload, got %s", source.ID)
+	}
+	if state, _ := LoadUploadState(videoMd5); state != nil {
+		t.Errorf("expected the upload state to be deleted, got %+v", state)
+	}
+}
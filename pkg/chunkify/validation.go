@@ -6,59 +6,78 @@ import (
 	"strings"
 )
 
+// flagError is a validation error caused by the value of a flag
+type flagError struct {
+	Flag string
+	Err  error
+}
+
+func (e *flagError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *flagError) Unwrap() error {
+	return e.Err
+}
+
+// flagErrorf returns the formatted error as caused by flag
+func flagErrorf(flag string, format string, a ...any) error {
+	return &flagError{Flag: flag, Err: fmt.Errorf(format, a...)}
+}
+
 func validateCommonVideoFlags() error {
 	if width != nil && *width != 0 {
 		if *width < 0 || *width > 8192 {
-			return fmt.Errorf("--resolution width must be between 0 and 8192")
+			return flagErrorf("resolution", "--resolution width must be between 0 and 8192")
 		}
 	}
 	if height != nil && *height != 0 {
 		if *height < 0 || *height > 8192 {
-			return fmt.Errorf("--resolution height must be between 0 and 8192")
+			return flagErrorf("resolution", "--resolution height must be between 0 and 8192")
 		}
 	}
 
 	if videoBitrate != nil && *videoBitrate != 0 {
 		if *videoBitrate < 100000 || *videoBitrate > 50000000 {
-			return fmt.Errorf("--vb must be between 100000 and 50000000")
+			return flagErrorf("vb", "--vb must be between 100000 and 50000000")
 		}
 	}
 	if audioBitrate != nil && *audioBitrate != 0 {
 		if *audioBitrate < 32000 || *audioBitrate > 512000 {
-			return fmt.Errorf("--ab must be between 32000 and 512000")
+			return flagErrorf("ab", "--ab must be between 32000 and 512000")
 		}
 	}
 
 	if framerate != nil && *framerate != 0 {
 		if *framerate < 15 || *framerate > 120 {
-			return fmt.Errorf("--framerate must be between 15 and 120")
+			return flagErrorf("framerate", "--framerate must be between 15 and 120")
 		}
 	}
 	if gop != nil && *gop != 0 {
 		if *gop < 1 || *gop > 300 {
-			return fmt.Errorf("--gop must be between 1 and 30")
+			return flagErrorf("gop", "--gop must be between 1 and 30")
 		}
 	}
 	if channels != nil && *channels != 0 {
 		if slices.Contains([]int64{1, 2, 5, 7}, *channels) {
-			return fmt.Errorf("--channels must be one of 1, 2, 5, 7")
+			return flagErrorf("channels", "--channels must be one of 1, 2, 5, 7")
 		}
 	}
 	if maxrate != nil && *maxrate != 0 {
 		if *maxrate < 100000 || *maxrate > 50000000 {
-			return fmt.Errorf("--maxrate must be between 100000 and 50000000")
+			return flagErrorf("maxrate", "--maxrate must be between 100000 and 50000000")
 		}
 	}
 	if bufsize != nil && *bufsize != 0 {
 		if *bufsize < 100000 || *bufsize > 50000000 {
-			return fmt.Errorf("--bufsize must be between 100000 and 50000000")
+			return flagErrorf("bufsize", "--bufsize must be between 100000 and 50000000")
 		}
 	}
 	if pixfmt != nil && *pixfmt != "" {
 		validPixFmts := []string{"yuv410p", "yuv411p", "yuv420p", "yuv422p", "yuv440p", "yuv444p", "yuvJ411p", "yuvJ420p", "yuvJ422p", "yuvJ440p", "yuvJ444p", "yuv420p10le", "yuv422p10le", "yuv440p10le", "yuv444p10le", "yuv420p12le", "yuv422p12le", "yuv440p12le", "yuv444p12le", "yuv420p10be", "yuv422p10be", "yuv440p10be", "yuv444p10be", "yuv420p12be", "yuv422p12be", "yuv440p12be", "yuv444p12be"}
 
 		if !slices.Contains(validPixFmts, *pixfmt) {
-			return fmt.Errorf("--pixfmt must be one of %s", strings.Join(validPixFmts, ", "))
+			return flagErrorf("pixfmt", "--pixfmt must be one of %s", strings.Join(validPixFmts, ", "))
 		}
 	}
 
@@ -67,27 +86,27 @@ func validateCommonVideoFlags() error {
 
 func validateH264Flags() error {
 	if crf != nil && ((*crf > 0 && *crf < 16) || *crf > 35) {
-		return fmt.Errorf("--crf must be between 16 and 35")
+		return flagErrorf("crf", "--crf must be between 16 and 35")
 	}
 	if preset != nil && *preset != "" {
 		if !slices.Contains([]string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium"}, *preset) {
-			return fmt.Errorf("--preset must be one of ultrafast, superfast, veryfast, faster, fast, medium, slow, slower, veryslow")
+			return flagErrorf("preset", "--preset must be one of ultrafast, superfast, veryfast, faster, fast, medium, slow, slower, veryslow")
 		}
 	}
 	if profilev != nil && *profilev != "" {
 		if !slices.Contains([]string{"baseline", "main", "high", "high10", "high422", "high444"}, *profilev) {
-			return fmt.Errorf("--profilev must be one of baseline, main, high, high10, high422, high444")
+			return flagErrorf("profilev", "--profilev must be one of baseline, main, high, high10, high422, high444")
 		}
 	}
 
 	if level != nil && *level != 0 {
 		if !slices.Contains([]int64{10, 11, 12, 13, 20, 21, 22, 30, 31, 32, 40, 41, 42, 50, 51}, *level) {
-			return fmt.Errorf("--level must be one of 10, 11, 12, 13, 20, 21, 22, 30, 31, 32, 40, 41, 42, 50, 51")
+			return flagErrorf("level", "--level must be one of 10, 11, 12, 13, 20, 21, 22, 30, 31, 32, 40, 41, 42, 50, 51")
 		}
 	}
 	if x264KeyInt != nil && *x264KeyInt != 0 {
 		if *x264KeyInt < 1 || *x264KeyInt > 300 {
-			return fmt.Errorf("--x264keyint must be between 1 and 30")
+			return flagErrorf("x264keyint", "--x264keyint must be between 1 and 30")
 		}
 	}
 
@@ -96,26 +115,26 @@ func validateH264Flags() error {
 
 func validateH265Flags() error {
 	if crf != nil && ((*crf > 0 && *crf < 16) || *crf > 35) {
-		return fmt.Errorf("--crf must be between 16 and 35")
+		return flagErrorf("crf", "--crf must be between 16 and 35")
 	}
 	if preset != nil && *preset != "" {
 		if !slices.Contains([]string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium"}, *preset) {
-			return fmt.Errorf("--preset must be one of ultrafast, superfast, veryfast, faster, fast, medium, slow, slower, veryslow")
+			return flagErrorf("preset", "--preset must be one of ultrafast, superfast, veryfast, faster, fast, medium, slow, slower, veryslow")
 		}
 	}
 	if profilev != nil && *profilev != "" {
 		if !slices.Contains([]string{"main", "main10", "mainstillpicture"}, *profilev) {
-			return fmt.Errorf("--profilev must be one of baseline, main, high, high10, high422, high444")
+			return flagErrorf("profilev", "--profilev must be one of baseline, main, high, high10, high422, high444")
 		}
 	}
 	if level != nil && *level != 0 {
 		if !slices.Contains([]int64{30, 31, 41}, *level) {
-			return fmt.Errorf("--level must be one of 30, 31, 41")
+			return flagErrorf("level", "--level must be one of 30, 31, 41")
 		}
 	}
 	if x265KeyInt != nil && *x265KeyInt != 0 {
 		if *x265KeyInt < 1 || *x265KeyInt > 300 {
-			return fmt.Errorf("--x265keyint must be between 1 and 30")
+			return flagErrorf("x265keyint", "--x265keyint must be between 1 and 30")
 		}
 	}
 
@@ -124,21 +143,21 @@ func validateH265Flags() error {
 
 func validateAv1Flags() error {
 	if crf != nil && ((*crf > 0 && *crf < 16) || *crf > 63) {
-		return fmt.Errorf("--crf must be between 16 and 63")
+		return flagErrorf("crf", "--crf must be between 16 and 63")
 	}
 	if preset != nil && *preset != "" {
 		if !slices.Contains([]string{"6", "7", "8", "9", "10", "11", "12", "13"}, *preset) {
-			return fmt.Errorf("--preset must be one of 6, 7, 8, 9, 10, 11, 12, 13")
+			return flagErrorf("preset", "--preset must be one of 6, 7, 8, 9, 10, 11, 12, 13")
 		}
 	}
 	if profilev != nil && *profilev != "" {
 		if !slices.Contains([]string{"main", "main10", "mainstillpicture"}, *profilev) {
-			return fmt.Errorf("--profilev must be one of main, main10, mainstillpicture")
+			return flagErrorf("profilev", "--profilev must be one of main, main10, mainstillpicture")
 		}
 	}
 	if level != nil && *level != 0 {
 		if !slices.Contains([]int64{30, 31, 41}, *level) {
-			return fmt.Errorf("--level must be one of 30, 31, 41")
+			return flagErrorf("level", "--level must be one of 30, 31, 41")
 		}
 	}
 	return nil
@@ -146,16 +165,16 @@ func validateAv1Flags() error {
 
 func validateWebmVp9Flags() error {
 	if crf != nil && (*crf < 15 || *crf > 35) {
-		return fmt.Errorf("--crf must be between 15 and 35")
+		return flagErrorf("crf", "--crf must be between 15 and 35")
 	}
 	if quality != nil && *quality != "" {
 		if !slices.Contains([]string{"good", "best", "realtime"}, *quality) {
-			return fmt.Errorf("--quality must be one of good, best, realtime")
+			return flagErrorf("quality", "--quality must be one of good, best, realtime")
 		}
 	}
 	if cpuUsed != nil && *cpuUsed != "" {
 		if !slices.Contains([]string{"0", "1", "2", "3", "4", "5", "6", "7", "8"}, *cpuUsed) {
-			return fmt.Errorf("--cpu-used must be one of 0, 1, 2, 3, 4, 5, 6, 7, 8")
+			return flagErrorf("cpu-used", "--cpu-used must be one of 0, 1, 2, 3, 4, 5, 6, 7, 8")
 		}
 	}
 
@@ -165,25 +184,25 @@ func validateWebmVp9Flags() error {
 func validateJpgFlags() error {
 	if interval != nil && *interval != 0 {
 		if *interval < 0 || *interval > 60 {
-			return fmt.Errorf("--interval must be between 0 and 60")
+			return flagErrorf("interval", "--interval must be between 0 and 60")
 		}
 	}
 	return nil
 }
 
 func validateHlsFlags() error {
-	if (videoBitrate == nil || *videoBitrate == 0) && (audioBitrate == nil || *audioBitrate == 0) {
-		return fmt.Errorf("--vb (video bitrate) or --ab (audio bitrate) are required when format is hls")
+	if (videoBitrate == nil || *videoBitrate == 0) && (audioBitrate == nil || *audioBitrate == 0) && (ladder == nil || *ladder == "") {
+		return flagErrorf("vb", "--vb (video bitrate) or --ab (audio bitrate) are required when format is hls")
 	}
 
 	if hlsTime != nil && *hlsTime != 0 {
 		if *hlsTime < 1 || *hlsTime > 10 {
-			return fmt.Errorf("--hls-time must be between 1 and 10")
+			return flagErrorf("hls-time", "--hls-time must be between 1 and 10")
 		}
 	}
 	if hlsSegmentType != nil && *hlsSegmentType != "" {
 		if !slices.Contains([]string{"mpegts", "fmp4"}, *hlsSegmentType) {
-			return fmt.Errorf("--hls-segment-type must be one of mpegts, fmp4")
+			return flagErrorf("hls-segment-type", "--hls-segment-type must be one of mpegts, fmp4")
 		}
 	}
 	return nil
@@ -27,6 +27,7 @@ func resetGlobalVars() {
 	hlsTime = nil
 	hlsSegmentType = nil
 	interval = nil
+	ladder = nil
 }
 
 func TestValidateCommonVideoFlags(t *testing.T) {
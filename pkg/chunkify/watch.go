@@ -0,0 +1,416 @@
+package chunkify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	WatchStateFileName = ".chunkify-watch.json"
+	WatchDoneDir       = "done"
+	WatchFailedDir     = "failed"
+
+	watchStatusProcessing = "processing"
+	watchStatusDone       = "done"
+	watchStatusFailed     = "failed"
+)
+
+// WatchCommand processes the videos dropped in a directory.
+// Processed videos are moved to done/ and failures to failed/ along with an error file
+type WatchCommand struct {
+	Dir         string        // Directory to watch
+	OutDir      string        // Directory where the outputs are downloaded
+	Output      string        // Output template of each input, relative to OutDir
+	Interval    time.Duration // How often the directory is scanned
+	Settle      time.Duration // How long a file must stop growing before being processed
+	Concurrency int           // Number of videos processed at the same time
+
+	// process runs the pipeline of one video, it can be replaced in tests
+	process func(ctx context.Context, input string) BatchResult
+
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	sem      chan struct{}
+	state    *watchState
+	pending  map[string]pendingFile // files seen but still growing
+	inFlight map[string]bool        // files being processed
+}
+
+// pendingFile is a file waiting to stop growing
+type pendingFile struct {
+	Size    int64
+	ModTime time.Time
+	Since   time.Time // when the file was last seen changing
+}
+
+// watchState is saved in the watched directory, so a restart neither re-processes nor skips files
+type watchState struct {
+	path  string
+	Files map[string]*watchedFile `json:"files"`
+}
+
+// watchedFile is the state of a file of the watched directory
+type watchedFile struct {
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	Status    string    `json:"status"`
+	SourceID  string    `json:"source_id,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWatchCommand(cfg *config.Config) *Command {
+	app := NewApp()
+	watch := &WatchCommand{}
+
+	cmd := &Command{
+		App:    app,
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "watch <dir>",
+			Short: "Transcode the videos dropped in a directory",
+			Long: `Transcode the videos dropped in a directory
+
+The directory is scanned for new videos. Once a video stops growing, it's uploaded, transcoded with the given settings and downloaded into --out.
+Processed videos are moved to the done/ directory, and failures to failed/ with an error file.
+
+The progress is saved in ` + WatchStateFileName + ` inside the watched directory, so the command can be restarted without processing a video twice or missing one.
+
+Examples:
+
+Package the videos dropped in incoming/ in HLS
+chunkify watch incoming/ --out processed/ -f hls_h264 --vb 2M --ab 128k
+
+Check for new videos every 10 seconds, 2 at a time
+chunkify watch incoming/ --out processed/ -o "{date}/{input_name}.{ext}" -f mp4_h264 --scan-interval 10s --concurrency 2
+`,
+			Args: cobra.ExactArgs(1),
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+					return fmt.Errorf("directory not found: %s", args[0])
+				}
+				if watch.Concurrency < 1 || watch.Concurrency > 32 {
+					return fmt.Errorf("--concurrency must be between 1 and 32")
+				}
+				if watch.Interval <= 0 || watch.Settle < 0 {
+					return fmt.Errorf("--scan-interval must be positive and --settle can't be negative")
+				}
+				if (watch.OutDir != "" || watch.Output != "") && app.Command.NoDownload {
+					return fmt.Errorf("--out and --output can't be used with --no-download, the outputs stay on the storage")
+				}
+				if err := validateBatchOutput(watch.Output); err != nil {
+					return err
+				}
+				return prepareCommand(app, cmd.Flags())
+			},
+			Run: func(cmd *cobra.Command, args []string) {
+				app.Client = cfg.Client
+				app.Command.Profile = cfg.Profile
+				watch.Dir = args[0]
+
+				ctx, stop := notifyPipelineInterrupts(context.Background(), app.Command.OnInterrupt)
+				defer stop()
+
+				watch.process = func(ctx context.Context, input string) BatchResult {
+					output := batchOutputs([]string{input}, watch.OutDir, watch.Output, app.Command.Format)[0]
+					return processInput(ctx, app, input, output)
+				}
+
+				if err := watch.Run(ctx); err != nil {
+					fmt.Printf("Error: %s\n", err)
+					os.Exit(1)
+				}
+			},
+		},
+	}
+
+	app.Command = &ChunkifyCommand{}
+	cmd.Command.Flags().StringVar(&watch.OutDir, "out", "", "Directory where the outputs are downloaded. When not set, the videos are only transcoded")
+	cmd.Command.Flags().StringVarP(&watch.Output, "output", "o", "", "Output path template of each video, relative to --out (e.g. {input_name}_{height}p.{ext})")
+	cmd.Command.Flags().DurationVar(&watch.Interval, "scan-interval", 2*time.Second, "How often the directory is scanned for new videos")
+	cmd.Command.Flags().DurationVar(&watch.Settle, "settle", 10*time.Second, "How long a video must stop growing before being processed")
+	cmd.Command.Flags().IntVar(&watch.Concurrency, "concurrency", 1, "Number of videos processed at the same time (1-32)")
+	bindOnInterruptFlag(app, cmd.Command)
+	bindTranscodeFlags(app, cmd.Command)
+
+	cmd.Command.MarkFlagRequired("format")
+
+	return cmd
+}
+
+// Run scans the directory until the context is cancelled.
+// It waits for the videos being processed before returning
+func (w *WatchCommand) Run(ctx context.Context) error {
+	if err := w.init(); err != nil {
+		return err
+	}
+
+	fmt.Printf("  Watching %s for new videos\n\n", w.Dir)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	w.scan(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			w.wg.Wait()
+			return nil
+		case now := <-ticker.C:
+			w.scan(ctx, now)
+		}
+	}
+}
+
+// init loads the state and moves the videos processed before a restart
+func (w *WatchCommand) init() error {
+	state, err := loadWatchState(filepath.Join(w.Dir, WatchStateFileName))
+	if err != nil {
+		return err
+	}
+
+	w.state = state
+	w.pending = map[string]pendingFile{}
+	w.inFlight = map[string]bool{}
+	w.sem = make(chan struct{}, max(w.Concurrency, 1))
+
+	for name, f := range state.Files {
+		if _, err := os.Stat(filepath.Join(w.Dir, name)); err != nil {
+			// the video was moved or deleted while stopped
+			delete(state.Files, name)
+			continue
+		}
+
+		// the video was processed but not moved yet
+		if f.Status == watchStatusDone || f.Status == watchStatusFailed {
+			w.finish(name, f)
+		}
+	}
+
+	return w.saveState()
+}
+
+// scan looks for videos that stopped growing and processes them
+func (w *WatchCommand) scan(ctx context.Context, now time.Time) {
+	entries, err := os.ReadDir(w.Dir)
+	if err != nil {
+		slog.Error("Cannot read watched directory", "dir", w.Dir, "error", err)
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !videoExtensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+		seen[name] = true
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		w.mu.Lock()
+		inFlight := w.inFlight[name]
+		f := w.state.Files[name]
+		w.mu.Unlock()
+		if inFlight {
+			continue
+		}
+
+		// processed but not moved, only the move is tried again
+		if f != nil && (f.Status == watchStatusDone || f.Status == watchStatusFailed) {
+			w.finish(name, f)
+			w.persistState()
+			continue
+		}
+
+		// wait for the file to stop growing
+		p, ok := w.pending[name]
+		if !ok || p.Size != info.Size() || !p.ModTime.Equal(info.ModTime()) {
+			w.pending[name] = pendingFile{Size: info.Size(), ModTime: info.ModTime(), Since: now}
+			continue
+		}
+		if now.Sub(p.Since) < w.Settle {
+			continue
+		}
+		delete(w.pending, name)
+
+		w.start(ctx, name, info)
+	}
+
+	// forget the files removed before being processed
+	for name := range w.pending {
+		if !seen[name] {
+			delete(w.pending, name)
+		}
+	}
+}
+
+// start processes a video in the background
+func (w *WatchCommand) start(ctx context.Context, name string, info os.FileInfo) {
+	w.mu.Lock()
+	w.inFlight[name] = true
+	w.state.Files[name] = &watchedFile{Size: info.Size(), ModTime: info.ModTime(), Status: watchStatusProcessing, UpdatedAt: time.Now()}
+	w.mu.Unlock()
+	w.persistState()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer func() {
+			w.mu.Lock()
+			delete(w.inFlight, name)
+			w.mu.Unlock()
+		}()
+
+		select {
+		case w.sem <- struct{}{}:
+			defer func() { <-w.sem }()
+		case <-ctx.Done():
+			return
+		}
+
+		input := filepath.Join(w.Dir, name)
+		fmt.Printf("  [%s] Processing %s\n", time.Now().Format(time.TimeOnly), input)
+
+		result := w.process(ctx, input)
+		if ctx.Err() != nil {
+			// stopped, the video stays in the processing state and will be processed again on restart
+			return
+		}
+
+		w.mu.Lock()
+		f := w.state.Files[name]
+		f.SourceID = result.SourceID
+		f.JobID = result.JobID
+		f.Output = result.Output
+		f.UpdatedAt = time.Now()
+		f.Status = watchStatusDone
+		if result.Err != nil {
+			f.Status = watchStatusFailed
+			f.Error = result.Err.Error()
+		}
+		w.mu.Unlock()
+		w.persistState()
+
+		if result.Err != nil {
+			slog.Error("Watched video failed", "input", input, "error", result.Err)
+			fmt.Printf("  [%s] %s %s: %s\n", time.Now().Format(time.TimeOnly), errorText("✗"), input, result.Err)
+		} else {
+			fmt.Printf("  [%s] %s %s → %s\n", time.Now().Format(time.TimeOnly), completedIcon, input, formatter.ValueOrDash(result.Output))
+		}
+
+		w.finish(name, f)
+		w.persistState()
+	}()
+}
+
+// finish moves a processed video to done/ or failed/ and forgets it
+func (w *WatchCommand) finish(name string, f *watchedFile) {
+	dir := filepath.Join(w.Dir, WatchDoneDir)
+	if f.Status == watchStatusFailed {
+		dir = filepath.Join(w.Dir, WatchFailedDir)
+	}
+
+	dest, err := moveFile(filepath.Join(w.Dir, name), dir)
+	if err != nil {
+		slog.Error("Cannot move watched video", "name", name, "error", err)
+		fmt.Printf("  Error moving %s: %s\n", name, err)
+		return
+	}
+
+	if f.Status == watchStatusFailed {
+		if err := os.WriteFile(dest+".error.txt", []byte(f.Error+"\n"), 0644); err != nil {
+			slog.Error("Cannot write error file", "name", name, "error", err)
+		}
+	}
+
+	w.mu.Lock()
+	delete(w.state.Files, name)
+	w.mu.Unlock()
+}
+
+// moveFile moves the file into dir. If a file with the same name already exists, a timestamp is added to the name
+func moveFile(file string, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	name := filepath.Base(file)
+	dest := filepath.Join(dir, name)
+	if _, err := os.Stat(dest); err == nil {
+		ext := filepath.Ext(name)
+		dest = filepath.Join(dir, fmt.Sprintf("%s_%s%s", strings.TrimSuffix(name, ext), time.Now().Format("20060102150405"), ext))
+	}
+
+	if err := os.Rename(file, dest); err != nil {
+		return "", fmt.Errorf("move file: %w", err)
+	}
+	return dest, nil
+}
+
+// loadWatchState reads the state file, an empty state is returned if it doesn't exist
+func loadWatchState(statePath string) (*watchState, error) {
+	state := &watchState{path: statePath, Files: map[string]*watchedFile{}}
+
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read watch state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode watch state %s: %w", statePath, err)
+	}
+	if state.Files == nil {
+		state.Files = map[string]*watchedFile{}
+	}
+
+	return state, nil
+}
+
+// persistState saves the state while watching, the videos keep being processed if it fails
+func (w *WatchCommand) persistState() {
+	if err := w.saveState(); err != nil {
+		slog.Error("Cannot save watch state", "error", err)
+		fmt.Printf("  Error saving the watch state: %s\n", err)
+	}
+}
+
+// saveState writes the state file atomically
+func (w *WatchCommand) saveState() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode watch state: %w", err)
+	}
+
+	tmp := w.state.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write watch state: %w", err)
+	}
+	if err := os.Rename(tmp, w.state.path); err != nil {
+		return fmt.Errorf("write watch state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package chunkify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestWatch(t *testing.T, process func(ctx context.Context, input string) BatchResult) *WatchCommand {
+	t.Helper()
+	return &WatchCommand{Dir: t.TempDir(), Settle: 5 * time.Second, Concurrency: 2, process: process}
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatch_ProcessesStableFiles(t *testing.T) {
+	var mu sync.Mutex
+	processed := []string{}
+
+	w := newTestWatch(t, func(ctx context.Context, input string) BatchResult {
+		mu.Lock()
+		processed = append(processed, filepath.Base(input))
+		mu.Unlock()
+		if strings.Contains(input, "bad") {
+			return BatchResult{Input: input, Err: fmt.Errorf("job failed")}
+		}
+		return BatchResult{Input: input, Output: "out/" + filepath.Base(input)}
+	})
+	if err := w.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(w.Dir, "good.mp4"), "video")
+	writeFile(t, filepath.Join(w.Dir, "bad.mov"), "video")
+	writeFile(t, filepath.Join(w.Dir, "notes.txt"), "not a video")
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// first time the files are seen
+	w.scan(ctx, now)
+	w.wg.Wait()
+	if len(processed) != 0 {
+		t.Fatalf("expected no file to be processed before settling, got %v", processed)
+	}
+
+	// the file is still growing
+	writeFile(t, filepath.Join(w.Dir, "bad.mov"), "bigger video")
+	w.scan(ctx, now.Add(6*time.Second))
+	w.wg.Wait()
+	if len(processed) != 1 || processed[0] != "good.mp4" {
+		t.Fatalf("expected good.mp4 to be processed, got %v", processed)
+	}
+
+	w.scan(ctx, now.Add(12*time.Second))
+	w.wg.Wait()
+	if len(processed) != 2 || processed[1] != "bad.mov" {
+		t.Fatalf("expected bad.mov to be processed, got %v", processed)
+	}
+
+	if _, err := os.Stat(filepath.Join(w.Dir, WatchDoneDir, "good.mp4")); err != nil {
+		t.Errorf("expected good.mp4 in done/: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(w.Dir, WatchFailedDir, "bad.mov")); err != nil {
+		t.Errorf("expected bad.mov in failed/: %v", err)
+	}
+	errorFile, err := os.ReadFile(filepath.Join(w.Dir, WatchFailedDir, "bad.mov.error.txt"))
+	if err != nil || !strings.Contains(string(errorFile), "job failed") {
+		t.Errorf("expected the error file, got %q, %v", errorFile, err)
+	}
+	if _, err := os.Stat(filepath.Join(w.Dir, "notes.txt")); err != nil {
+		t.Errorf("expected notes.txt to be ignored: %v", err)
+	}
+
+	state, err := loadWatchState(filepath.Join(w.Dir, WatchStateFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Files) != 0 {
+		t.Errorf("expected the state to be empty, got %v", state.Files)
+	}
+}
+
+func TestWatch_Restart(t *testing.T) {
+	processed := []string{}
+	w := newTestWatch(t, func(ctx context.Context, input string) BatchResult {
+		processed = append(processed, filepath.Base(input))
+		return BatchResult{Input: input}
+	})
+	w.Concurrency = 1
+
+	// done.mp4 was processed but not moved, pending.mp4 was being processed when stopped
+	writeFile(t, filepath.Join(w.Dir, "done.mp4"), "video")
+	writeFile(t, filepath.Join(w.Dir, "pending.mp4"), "video")
+	state := &watchState{
+		path: filepath.Join(w.Dir, WatchStateFileName),
+		Files: map[string]*watchedFile{
+			"done.mp4":    {Status: watchStatusDone},
+			"pending.mp4": {Status: watchStatusProcessing},
+			"gone.mp4":    {Status: watchStatusProcessing},
+		},
+	}
+	w.state = state
+	if err := w.saveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(w.Dir, WatchDoneDir, "done.mp4")); err != nil {
+		t.Errorf("expected done.mp4 to be moved without processing it: %v", err)
+	}
+	if _, ok := w.state.Files["gone.mp4"]; ok {
+		t.Errorf("expected gone.mp4 to be removed from the state")
+	}
+
+	now := time.Now()
+	w.scan(context.Background(), now)
+	w.scan(context.Background(), now.Add(10*time.Second))
+	w.wg.Wait()
+
+	if len(processed) != 1 || processed[0] != "pending.mp4" {
+		t.Errorf("expected only pending.mp4 to be processed again, got %v", processed)
+	}
+}
+
+func TestWatch_InterruptedKeepsFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newTestWatch(t, func(ctx context.Context, input string) BatchResult {
+		cancel()
+		return BatchResult{Input: input, Err: ctx.Err()}
+	})
+	if err := w.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(w.Dir, "video.mp4"), "video")
+	now := time.Now()
+	w.scan(ctx, now)
+	w.scan(ctx, now.Add(10*time.Second))
+	w.wg.Wait()
+
+	if _, err := os.Stat(filepath.Join(w.Dir, "video.mp4")); err != nil {
+		t.Errorf("expected the interrupted video to stay in place: %v", err)
+	}
+
+	state, err := loadWatchState(filepath.Join(w.Dir, WatchStateFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, ok := state.Files["video.mp4"]; !ok || f.Status != watchStatusProcessing {
+		t.Errorf("expected the video to stay in the processing state, got %+v", state.Files)
+	}
+}
+
+func TestWatch_RetriesMove(t *testing.T) {
+	processed := 0
+	w := newTestWatch(t, func(ctx context.Context, input string) BatchResult {
+		processed++
+		return BatchResult{Input: input}
+	})
+	if err := w.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a file in place of the done directory makes the move fail
+	writeFile(t, filepath.Join(w.Dir, WatchDoneDir), "not a directory")
+	writeFile(t, filepath.Join(w.Dir, "video.mp4"), "video")
+
+	ctx := context.Background()
+	now := time.Now()
+	w.scan(ctx, now)
+	w.scan(ctx, now.Add(10*time.Second))
+	w.wg.Wait()
+	if processed != 1 {
+		t.Fatalf("expected the video to be processed once, got %d", processed)
+	}
+
+	if err := os.Remove(filepath.Join(w.Dir, WatchDoneDir)); err != nil {
+		t.Fatal(err)
+	}
+	w.scan(ctx, now.Add(20*time.Second))
+	w.scan(ctx, now.Add(30*time.Second))
+	w.wg.Wait()
+
+	if processed != 1 {
+		t.Errorf("expected the video not to be processed again, got %d", processed)
+	}
+	if _, err := os.Stat(filepath.Join(w.Dir, WatchDoneDir, "video.mp4")); err != nil {
+		t.Errorf("expected video.mp4 to be moved on the next scan: %v", err)
+	}
+	if _, ok := w.state.Files["video.mp4"]; ok {
+		t.Errorf("expected video.mp4 to be removed from the state")
+	}
+}
+
+func TestMoveFile_ExistingName(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "done")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dest, "video.mp4"), "old")
+	writeFile(t, filepath.Join(dir, "video.mp4"), "new")
+
+	moved, err := moveFile(filepath.Join(dir, "video.mp4"), dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved == filepath.Join(dest, "video.mp4") {
+		t.Errorf("expected a new name, the existing file would be overwritten")
+	}
+	if content, _ := os.ReadFile(filepath.Join(dest, "video.mp4")); string(content) != "old" {
+		t.Errorf("expected the existing file to be kept")
+	}
+}
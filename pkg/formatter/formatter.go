@@ -1,11 +1,15 @@
 package formatter
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
+
+	"github.com/chunkifydev/chunkify-go/packages/param"
 )
 
 // Duration formats a duration in seconds into a human readable string (HH:MM:SS)
@@ -115,3 +119,70 @@ func ParseFileSize(sizeStr string) (int64, error) {
 
 	return int64(value * float64(multiplier)), nil
 }
+
+// ParseTime parses a date (2006-01-02) in local time, a RFC3339 time or a duration before now (e.g. "24h")
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%s is not a date (2006-01-02), a time (RFC3339) or a duration (24h)", value)
+}
+
+// ParseCreated parses the --created-after and --created-before flags of the list commands into the
+// gte and lte filters of the API, in seconds since epoch. The filter of an empty flag is left unset
+func ParseCreated(after string, before string, now time.Time) (gte param.Opt[int64], lte param.Opt[int64], err error) {
+	if after != "" {
+		t, err := ParseTime(after, now)
+		if err != nil {
+			return gte, lte, fmt.Errorf("invalid --created-after: %w", err)
+		}
+		gte = param.NewOpt(t.Unix())
+	}
+
+	if before != "" {
+		t, err := ParseTime(before, now)
+		if err != nil {
+			return gte, lte, fmt.Errorf("invalid --created-before: %w", err)
+		}
+		lte = param.NewOpt(t.Unix())
+	}
+
+	return gte, lte, nil
+}
+
+// ParseMetadata parses the key=value metadata filters of the list commands
+func ParseMetadata(filters []string) ([][]string, error) {
+	var metadata [][]string
+	for _, kv := range filters {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid metadata filter: %s. Expected key=value", kv)
+		}
+		metadata = append(metadata, []string{key, value})
+	}
+	return metadata, nil
+}
+
+// PrintJSON writes v as JSON on a single line
+func PrintJSON(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// ValueOrDash returns s, or a dash for the empty cells of a table
+func ValueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
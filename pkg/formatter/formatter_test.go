@@ -1,6 +1,9 @@
 package formatter
 
 import (
+	"bytes"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -316,3 +319,102 @@ func TestParseFileSize(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "date",
+			value:    "2024-01-02",
+			expected: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "rfc3339",
+			value:    "2024-02-01T10:00:00Z",
+			expected: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "duration",
+			value:    "24h",
+			expected: time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "invalid",
+			value:       "yesterday",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseTime(tt.value, now)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if !result.Equal(tt.expected) {
+					t.Errorf("Expected %s, got %s", tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCreated(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	gte, lte, err := ParseCreated("24h", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gte.Value != now.Add(-24*time.Hour).Unix() || lte.Valid() {
+		t.Errorf("Expected only gte to be set, got %v and %v", gte, lte)
+	}
+
+	if _, _, err := ParseCreated("", "yesterday", now); err == nil || !strings.Contains(err.Error(), "--created-before") {
+		t.Errorf("Expected an invalid --created-before error, got %v", err)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := ParseMetadata([]string{"origin=chunkify/cli", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata) != 2 || !slices.Equal(metadata[0], []string{"origin", "chunkify/cli"}) || !slices.Equal(metadata[1], []string{"empty", ""}) {
+		t.Errorf("Unexpected metadata %v", metadata)
+	}
+
+	for _, filter := range []string{"origin", "=value"} {
+		if _, err := ParseMetadata([]string{filter}); err == nil {
+			t.Errorf("Expected an error for %q", filter)
+		}
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintJSON(&buf, map[string]string{"id": "job_1"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `{"id":"job_1"}`+"\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestValueOrDash(t *testing.T) {
+	if ValueOrDash("") != "-" || ValueOrDash("src_1") != "src_1" {
+		t.Error("Expected a dash for the empty values only")
+	}
+}
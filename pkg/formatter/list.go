@@ -0,0 +1,32 @@
+package formatter
+
+// PageSize is the maximum number of items returned by the API per page
+const PageSize = 100
+
+// Paginate fetches the pages of a list until limit items are returned. A limit of 0 returns all the items.
+// fetch returns the page of at most size items starting at offset, along with the total number of items,
+// or -1 when the API doesn't report it: the list then ends with the first page that isn't full
+func Paginate[T any](limit int64, fetch func(offset int64, size int64) ([]T, int64, error)) ([]T, error) {
+	items := []T{}
+	offset := int64(0)
+
+	for limit == 0 || int64(len(items)) < limit {
+		size := int64(PageSize)
+		if limit > 0 {
+			size = min(size, limit-int64(len(items)))
+		}
+
+		page, total, err := fetch(offset, size)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page...)
+		offset += int64(len(page))
+		if len(page) == 0 || (total < 0 && int64(len(page)) < size) || (total >= 0 && offset >= total) {
+			break
+		}
+	}
+
+	return items, nil
+}
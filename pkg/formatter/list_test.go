@@ -0,0 +1,63 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+// fetchRange returns a fetch of total items, reporting the total or not
+func fetchRange(total int64, reportTotal bool, requests *int) func(int64, int64) ([]int64, int64, error) {
+	return func(offset int64, size int64) ([]int64, int64, error) {
+		*requests++
+		page := []int64{}
+		for i := offset; i < min(offset+size, total); i++ {
+			page = append(page, i)
+		}
+		if !reportTotal {
+			return page, -1, nil
+		}
+		return page, total, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int64
+		reportTotal bool
+		limit       int64
+		expected    int
+		requests    int
+	}{
+		{name: "all", total: 230, reportTotal: true, expected: 230, requests: 3},
+		{name: "limit", total: 230, reportTotal: true, limit: 150, expected: 150, requests: 2},
+		{name: "full last page", total: 200, reportTotal: true, expected: 200, requests: 2},
+		{name: "without total", total: 230, expected: 230, requests: 3},
+		// the end is only known from an empty page
+		{name: "without total, full last page", total: 200, expected: 200, requests: 3},
+		{name: "empty", total: 0, reportTotal: true, expected: 0, requests: 1},
+	}
+
+	for _, tt := range tests {
+		requests := 0
+		items, err := Paginate(tt.limit, fetchRange(tt.total, tt.reportTotal, &requests))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(items) != tt.expected || requests != tt.requests {
+			t.Errorf("%s: expected %d items in %d requests, got %d in %d", tt.name, tt.expected, tt.requests, len(items), requests)
+		}
+		for i, item := range items {
+			if item != int64(i) {
+				t.Errorf("%s: unexpected item %d at %d", tt.name, item, i)
+				break
+			}
+		}
+	}
+
+	errFetch := errors.New("fetch failed")
+	_, err := Paginate(0, func(int64, int64) ([]int64, int64, error) { return nil, 0, errFetch })
+	if !errors.Is(err, errFetch) {
+		t.Errorf("Expected %v, got %v", errFetch, err)
+	}
+}
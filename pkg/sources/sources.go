@@ -0,0 +1,266 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+const indent = "  "
+
+// Command represents the root sources command and configuration
+type Command struct {
+	Command *cobra.Command // The root cobra command for sources
+	Config  *config.Config // Configuration for the sources command
+}
+
+type ChunkifyClientInterface interface {
+	SourceList(ctx context.Context, params chunkify.SourceListParams) ([]chunkify.Source, int64, error)
+	SourceGet(ctx context.Context, sourceId string) (*chunkify.Source, error)
+	SourceDelete(ctx context.Context, sourceId string) error
+}
+
+type ChunkifyClient struct {
+	Client *chunkify.Client
+}
+
+func (c *ChunkifyClient) SourceList(ctx context.Context, params chunkify.SourceListParams) ([]chunkify.Source, int64, error) {
+	res, err := c.Client.Sources.List(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Data, res.Total, nil
+}
+
+func (c *ChunkifyClient) SourceGet(ctx context.Context, sourceId string) (*chunkify.Source, error) {
+	return c.Client.Sources.Get(ctx, sourceId)
+}
+
+func (c *ChunkifyClient) SourceDelete(ctx context.Context, sourceId string) error {
+	return c.Client.Sources.Delete(ctx, sourceId)
+}
+
+// ListFilters holds the filters of the list command
+type ListFilters struct {
+	Metadata      []string // key=value pairs the sources metadata must contain
+	CreatedAfter  string   // date, RFC3339 time or duration ago
+	CreatedBefore string   // date, RFC3339 time or duration ago
+	Limit         int64    // maximum number of sources listed, 0 lists all
+}
+
+// NewCommand creates and configures a new sources root command
+func NewCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "sources",
+			Short: "List, inspect and delete sources",
+			Long: `List, inspect and delete the sources of the project
+
+Examples:
+
+chunkify sources list --metadata origin=chunkify/cli --created-after 24h
+chunkify sources get src_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify sources delete src_2G6MJiNz71bHQGNzGwKx5cJwPFS
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newListCommand(cfg))
+	cmd.Command.AddCommand(newGetCommand(cfg))
+	cmd.Command.AddCommand(newDeleteCommand(cfg))
+
+	return cmd
+}
+
+func newListCommand(cfg *config.Config) *cobra.Command {
+	filters := ListFilters{}
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the sources, most recent first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params, err := filters.Params()
+			if err != nil {
+				return err
+			}
+
+			client := &ChunkifyClient{Client: cfg.Client}
+			sources, err := List(cmd.Context(), client, params, filters.Limit)
+			if err != nil {
+				return fmt.Errorf("error listing sources: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, sources)
+			}
+			return printSources(os.Stdout, sources)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&filters.Metadata, "metadata", nil, "Only list the sources with the given metadata, as key=value. Can be repeated")
+	cmd.Flags().StringVar(&filters.CreatedAfter, "created-after", "", "Only list the sources created after the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().StringVar(&filters.CreatedBefore, "created-before", "", "Only list the sources created before the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().Int64Var(&filters.Limit, "limit", 0, "Maximum number of sources to list. All the sources are listed by default")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newGetCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "get <src_id>",
+		Short:        "Show the details of a source",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			source, err := client.SourceGet(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("error getting source: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, source)
+			}
+			return printSource(os.Stdout, source)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newDeleteCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete <src_id>...",
+		Short:        "Delete one or more sources",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+
+			errs := []error{}
+			for _, id := range args {
+				if err := client.SourceDelete(cmd.Context(), id); err != nil {
+					errs = append(errs, fmt.Errorf("error deleting source %s: %w", id, err))
+					continue
+				}
+				fmt.Printf("%sSource %s deleted\n", indent, id)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// Params converts the filters to the list params, sorted by creation date, most recent first
+func (f ListFilters) Params() (chunkify.SourceListParams, error) {
+	params := chunkify.SourceListParams{
+		Created: chunkify.SourceListParamsCreated{Sort: "desc"},
+	}
+
+	metadata, err := formatter.ParseMetadata(f.Metadata)
+	if err != nil {
+		return params, err
+	}
+	params.Metadata = metadata
+
+	params.Created.Gte, params.Created.Lte, err = formatter.ParseCreated(f.CreatedAfter, f.CreatedBefore, time.Now())
+	if err != nil {
+		return params, err
+	}
+
+	if f.Limit < 0 {
+		return params, fmt.Errorf("--limit must be positive")
+	}
+
+	return params, nil
+}
+
+// List returns the sources matching params, fetching the pages until limit is reached.
+// A limit of 0 returns all the sources
+func List(ctx context.Context, client ChunkifyClientInterface, params chunkify.SourceListParams, limit int64) ([]chunkify.Source, error) {
+	return formatter.Paginate(limit, func(offset int64, size int64) ([]chunkify.Source, int64, error) {
+		params.Limit = chunkify.Int(size)
+		params.Offset = chunkify.Int(offset)
+		return client.SourceList(ctx, params)
+	})
+}
+
+func printSources(w io.Writer, sources []chunkify.Source) error {
+	if len(sources) == 0 {
+		fmt.Fprintln(w, indent+"No source found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tCREATED\tDURATION\tSIZE\tRESOLUTION\tCODECS")
+	for _, s := range sources {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%dx%d\t%s\n",
+			indent,
+			s.ID,
+			s.CreatedAt.Local().Format(time.DateTime),
+			formatter.Duration(s.Duration),
+			formatter.Size(s.Size),
+			s.Width,
+			s.Height,
+			codecs(s))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(sources))
+	return nil
+}
+
+func printSource(w io.Writer, s *chunkify.Source) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%sID\t%s\n", indent, s.ID)
+	fmt.Fprintf(tw, "%sCreated\t%s\n", indent, s.CreatedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(tw, "%sDuration\t%s\n", indent, formatter.Duration(s.Duration))
+	fmt.Fprintf(tw, "%sSize\t%s\n", indent, formatter.Size(s.Size))
+	fmt.Fprintf(tw, "%sResolution\t%dx%d\n", indent, s.Width, s.Height)
+	fmt.Fprintf(tw, "%sVideo\t%s %s %.2f fps\n", indent, formatter.ValueOrDash(s.VideoCodec), formatter.Bitrate(s.VideoBitrate), s.VideoFramerate)
+	fmt.Fprintf(tw, "%sAudio\t%s %s\n", indent, formatter.ValueOrDash(s.AudioCodec), formatter.Bitrate(s.AudioBitrate))
+	fmt.Fprintf(tw, "%sDevice\t%s\n", indent, formatter.ValueOrDash(s.Device))
+	fmt.Fprintf(tw, "%sURL\t%s\n", indent, s.URL)
+
+	keys := make([]string, 0, len(s.Metadata))
+	for key := range s.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		label := ""
+		if i == 0 {
+			label = "Metadata"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s=%s\n", indent, label, key, s.Metadata[key])
+	}
+
+	return tw.Flush()
+}
+
+func codecs(s chunkify.Source) string {
+	if s.AudioCodec == "" {
+		return formatter.ValueOrDash(s.VideoCodec)
+	}
+	return formatter.ValueOrDash(s.VideoCodec) + "/" + s.AudioCodec
+}
@@ -0,0 +1,147 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// Mock client for testing
+type MockChunkifyClient struct {
+	sources []chunkify.Source
+	calls   []chunkify.SourceListParams
+}
+
+func (m *MockChunkifyClient) SourceList(ctx context.Context, params chunkify.SourceListParams) ([]chunkify.Source, int64, error) {
+	m.calls = append(m.calls, params)
+	start := min(params.Offset.Value, int64(len(m.sources)))
+	end := min(start+params.Limit.Value, int64(len(m.sources)))
+	return m.sources[start:end], int64(len(m.sources)), nil
+}
+
+func (m *MockChunkifyClient) SourceGet(ctx context.Context, sourceId string) (*chunkify.Source, error) {
+	for _, s := range m.sources {
+		if s.ID == sourceId {
+			return &s, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func (m *MockChunkifyClient) SourceDelete(ctx context.Context, sourceId string) error {
+	return nil
+}
+
+func newMockClient(n int) *MockChunkifyClient {
+	m := &MockChunkifyClient{}
+	for i := range n {
+		m.sources = append(m.sources, chunkify.Source{ID: fmt.Sprintf("src_%d", i)})
+	}
+	return m
+}
+
+func TestList_Pagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		sources   int
+		limit     int64
+		wantCount int
+		wantCalls int
+	}{
+		{name: "empty", sources: 0, wantCount: 0, wantCalls: 1},
+		{name: "one page", sources: 42, wantCount: 42, wantCalls: 1},
+		{name: "all pages", sources: 250, wantCount: 250, wantCalls: 3},
+		{name: "limit", sources: 250, limit: 120, wantCount: 120, wantCalls: 2},
+		{name: "limit above total", sources: 30, limit: 50, wantCount: 30, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMockClient(tt.sources)
+			sources, err := List(context.Background(), client, chunkify.SourceListParams{}, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sources) != tt.wantCount {
+				t.Errorf("expected %d sources, got %d", tt.wantCount, len(sources))
+			}
+			if len(client.calls) != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, len(client.calls))
+			}
+			for i, s := range sources {
+				if s.ID != fmt.Sprintf("src_%d", i) {
+					t.Fatalf("expected src_%d at index %d, got %s", i, i, s.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestListFilters_Params(t *testing.T) {
+	filters := ListFilters{
+		Metadata:      []string{"origin=chunkify/cli", "md5=abc"},
+		CreatedAfter:  "2024-01-02",
+		CreatedBefore: "2024-02-01T10:00:00Z",
+	}
+
+	params, err := filters.Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params.Metadata) != 2 || params.Metadata[0][0] != "origin" || params.Metadata[0][1] != "chunkify/cli" {
+		t.Errorf("unexpected metadata: %v", params.Metadata)
+	}
+	after := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local).Unix()
+	if params.Created.Gte.Value != after {
+		t.Errorf("expected created gte %d, got %d", after, params.Created.Gte.Value)
+	}
+	if params.Created.Lte.Value != time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("unexpected created lte: %d", params.Created.Lte.Value)
+	}
+	if params.Created.Sort != "desc" {
+		t.Errorf("expected the most recent sources first, got %s", params.Created.Sort)
+	}
+}
+
+func TestListFilters_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters ListFilters
+		wantErr string
+	}{
+		{name: "metadata without value", filters: ListFilters{Metadata: []string{"origin"}}, wantErr: "invalid metadata filter"},
+		{name: "invalid date", filters: ListFilters{CreatedAfter: "yesterday"}, wantErr: "invalid --created-after"},
+		{name: "negative limit", filters: ListFilters{Limit: -1}, wantErr: "--limit must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.filters.Params(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPrintSources(t *testing.T) {
+	var buf bytes.Buffer
+	err := printSources(&buf, []chunkify.Source{
+		{ID: "src_1", Duration: 90, Size: 2048, Width: 1920, Height: 1080, VideoCodec: "h264", AudioCodec: "aac"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"src_1", "01:30", "2KB", "1920x1080", "h264/aac", "Total: 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package storages
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+const indent = "  "
+
+// Command represents the root storages command and configuration
+type Command struct {
+	Command *cobra.Command // The root cobra command for storages
+	Config  *config.Config // Configuration for the storages command
+}
+
+type ChunkifyClientInterface interface {
+	StorageList(ctx context.Context) ([]chunkify.StorageUnion, error)
+	StorageGet(ctx context.Context, storageId string) (*chunkify.StorageUnion, error)
+}
+
+type ChunkifyClient struct {
+	Client *chunkify.Client
+}
+
+func (c *ChunkifyClient) StorageList(ctx context.Context) ([]chunkify.StorageUnion, error) {
+	res, err := c.Client.Storages.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+func (c *ChunkifyClient) StorageGet(ctx context.Context, storageId string) (*chunkify.StorageUnion, error) {
+	return c.Client.Storages.Get(ctx, storageId)
+}
+
+// NewCommand creates and configures a new storages root command
+func NewCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "storages",
+			Short: "List the storages of the project",
+			Long: `List the storages configured for the project
+
+The ID of a storage can be given to --storage-id to keep the outputs of a job on it.
+
+Examples:
+
+chunkify storages list
+chunkify storages get aws-prod
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newListCommand(cfg))
+	cmd.Command.AddCommand(newGetCommand(cfg))
+
+	return cmd
+}
+
+func newListCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the storages",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			storages, err := client.StorageList(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error listing storages: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, storages)
+			}
+			return printStorages(os.Stdout, storages)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newGetCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "get <storage_id>",
+		Short:        "Show the details of a storage",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			storage, err := client.StorageGet(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("error getting storage: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, storage)
+			}
+			return printStorage(os.Stdout, storage)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func printStorages(w io.Writer, storages []chunkify.StorageUnion) error {
+	if len(storages) == 0 {
+		fmt.Fprintln(w, indent+"No storage found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tSLUG\tPROVIDER\tREGION\tBUCKET\tPUBLIC")
+	for _, s := range storages {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\t%t\n",
+			indent,
+			s.ID,
+			formatter.ValueOrDash(s.Slug),
+			s.Provider,
+			formatter.ValueOrDash(s.Region),
+			formatter.ValueOrDash(s.Bucket),
+			s.Public)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(storages))
+	return nil
+}
+
+func printStorage(w io.Writer, s *chunkify.StorageUnion) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%sID\t%s\n", indent, s.ID)
+	fmt.Fprintf(tw, "%sSlug\t%s\n", indent, formatter.ValueOrDash(s.Slug))
+	fmt.Fprintf(tw, "%sCreated\t%s\n", indent, s.CreatedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(tw, "%sProvider\t%s\n", indent, s.Provider)
+	fmt.Fprintf(tw, "%sRegion\t%s\n", indent, formatter.ValueOrDash(s.Region))
+	fmt.Fprintf(tw, "%sBucket\t%s\n", indent, formatter.ValueOrDash(s.Bucket))
+	if s.Endpoint != "" {
+		fmt.Fprintf(tw, "%sEndpoint\t%s\n", indent, s.Endpoint)
+	}
+	if s.Location != "" {
+		fmt.Fprintf(tw, "%sLocation\t%s\n", indent, s.Location)
+	}
+	fmt.Fprintf(tw, "%sPublic\t%t\n", indent, s.Public)
+
+	return tw.Flush()
+}
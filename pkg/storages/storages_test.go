@@ -0,0 +1,57 @@
+package storages
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestPrintStorages(t *testing.T) {
+	storages := []chunkify.StorageUnion{}
+	err := json.Unmarshal([]byte(`[
+		{"id":"stor_1","slug":"chunkify-default","provider":"chunkify","region":"us-east-1"},
+		{"id":"stor_2","slug":"aws-prod","provider":"aws","region":"eu-west-1","bucket":"videos","public":true}
+	]`), &storages)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := printStorages(&buf, storages); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"stor_1", "chunkify-default", "aws-prod", "eu-west-1", "videos", "true", "Total: 2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := printStorages(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No storage found") {
+		t.Errorf("unexpected output for no storage: %s", buf.String())
+	}
+}
+
+func TestPrintStorage(t *testing.T) {
+	storage := &chunkify.StorageUnion{ID: "stor_2", Slug: "r2-prod", Provider: "cloudflare", Location: "WNAM", Endpoint: "https://r2.example.com"}
+
+	var buf bytes.Buffer
+	if err := printStorage(&buf, storage); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"r2-prod", "cloudflare", "https://r2.example.com", "WNAM", "Bucket    -"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
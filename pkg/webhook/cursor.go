@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Cursor is the last notification forwarded by listen. It's saved locally, per profile and hostname,
+// so the next run forwards the notifications received in the meantime instead of starting from now
+type Cursor struct {
+	WebhookID      string    `json:"webhook_id"`
+	NotificationID string    `json:"notification_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	// IDs of the notifications forwarded in the same second as CreatedAt,
+	// the API filters by second so they are listed again
+	Seen []string `json:"seen,omitempty"`
+}
+
+// cursorDir can be overridden in tests
+var cursorDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "chunkify", "listen"), nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// cursorPath returns the file of the cursor of the localdev webhook of hostname
+func cursorPath(profile string, hostname string) (string, error) {
+	return listenFilePath(profile, hostname, ".json")
+}
+
+// deadLetterPath returns the default dead letter file of the listen of hostname, next to its cursor
+func deadLetterPath(profile string, hostname string) (string, error) {
+	return listenFilePath(profile, hostname, ".deadletter.ndjson")
+}
+
+// listenFilePath returns a file of the listen of hostname in the cursor dir, per profile and hostname
+func listenFilePath(profile string, hostname string, ext string) (string, error) {
+	dir, err := cursorDir()
+	if err != nil {
+		return "", fmt.Errorf("cursor dir: %w", err)
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	name := unsafeFileChars.ReplaceAllString(profile+"_"+hostname, "_")
+	return filepath.Join(dir, name+ext), nil
+}
+
+// LoadCursor returns the cursor saved at path, or nil if there is none
+func LoadCursor(path string) (*Cursor, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cursor: %w", err)
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SaveCursor persists the cursor at path. The file is replaced atomically
+// so a listener killed while saving doesn't leave a truncated cursor
+func SaveCursor(path string, cursor Cursor) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create cursor dir: %w", err)
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("encode cursor: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write cursor: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write cursor: %w", err)
+	}
+	return nil
+}
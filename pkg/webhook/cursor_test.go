@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestCursor_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listen", "default_host.json")
+
+	cursor, err := LoadCursor(path)
+	if err != nil || cursor != nil {
+		t.Fatalf("Expected no cursor, got %v, %v", cursor, err)
+	}
+
+	saved := Cursor{WebhookID: "wh_1", NotificationID: "notf_2", CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Seen: []string{"notf_1", "notf_2"}}
+	if err := SaveCursor(path, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err = LoadCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.WebhookID != saved.WebhookID || cursor.NotificationID != saved.NotificationID || !cursor.CreatedAt.Equal(saved.CreatedAt) || len(cursor.Seen) != 2 {
+		t.Errorf("Expected %+v, got %+v", saved, cursor)
+	}
+}
+
+func TestCursorPath(t *testing.T) {
+	dir := t.TempDir()
+	previous := cursorDir
+	cursorDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { cursorDir = previous })
+
+	path, err := cursorPath("", "mac/home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "default_mac_home.json") {
+		t.Errorf("Unexpected cursor path %s", path)
+	}
+
+	path, _ = cursorPath("staging", "mac")
+	if path != filepath.Join(dir, "staging_mac.json") {
+		t.Errorf("Unexpected cursor path %s", path)
+	}
+
+	path, _ = deadLetterPath("staging", "mac")
+	if path != filepath.Join(dir, "staging_mac.deadletter.ndjson") {
+		t.Errorf("Unexpected dead letter path %s", path)
+	}
+}
+
+func TestWebhookProxy_StartCursor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "cursor.json")
+
+	proxy := &WebhookProxy{WebhookId: "wh_1"}
+	if err := proxy.startCursor(path, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if !proxy.Cursor.CreatedAt.Equal(now) || proxy.Cursor.NotificationID != "" {
+		t.Errorf("Expected to start from now without cursor, got %+v", proxy.Cursor)
+	}
+
+	saved := Cursor{WebhookID: "wh_1", NotificationID: "notf_1", CreatedAt: now.Add(-time.Hour)}
+	if err := SaveCursor(path, saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := proxy.startCursor(path, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if proxy.Cursor.NotificationID != "notf_1" || !proxy.seen["notf_1"] {
+		t.Errorf("Expected to resume after notf_1, got %+v", proxy.Cursor)
+	}
+
+	// the cursor of a deleted webhook
+	other := &WebhookProxy{WebhookId: "wh_2"}
+	if err := other.startCursor(path, "", now); err != nil {
+		t.Fatal(err)
+	}
+	if !other.Cursor.CreatedAt.Equal(now) || other.Cursor.NotificationID != "" || other.seen["notf_1"] {
+		t.Errorf("Expected the cursor of another webhook to be ignored, got %+v", other.Cursor)
+	}
+
+	if err := proxy.startCursor(path, "2h", now); err != nil {
+		t.Fatal(err)
+	}
+	if !proxy.Cursor.CreatedAt.Equal(now.Add(-2*time.Hour)) || len(proxy.seen) != 0 {
+		t.Errorf("Expected to start 2h ago, got %+v", proxy.Cursor)
+	}
+
+	if err := proxy.startCursor(path, "yesterday", now); err == nil {
+		t.Error("Expected an error for an invalid --since")
+	}
+}
+
+func TestWebhookProxy_Execute_Pagination(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockClient := &MockChunkifyClient{}
+	for i := range 250 {
+		mockClient.notifications = append(mockClient.notifications, chunkify.Notification{
+			ID:        fmt.Sprintf("notf_%d", i),
+			Event:     "job.completed",
+			CreatedAt: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	proxy := &WebhookProxy{Client: mockClient, Events: []string{"job.completed"}, Cursor: Cursor{CreatedAt: start.Add(10 * time.Second)}}
+
+	notifications, err := proxy.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 240 {
+		t.Errorf("Expected the 240 notifications since the cursor, got %d", len(notifications))
+	}
+}
+
+func TestWebhookProxy_Resume(t *testing.T) {
+	var mu sync.Mutex
+	forwarded := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		forwarded = append(forwarded, r.Header.Get("webhook-id"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	created := time.Now().Add(time.Minute).Truncate(time.Second)
+	mockClient := &MockChunkifyClient{notifications: []chunkify.Notification{
+		{ID: "notf_1", Event: "job.completed", CreatedAt: created},
+		{ID: "notf_2", Event: "job.completed", CreatedAt: created},
+	}}
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	forward := func() {
+		proxy := &WebhookProxy{Client: mockClient, localUrl: server.URL, webhookSecret: secret, WebhookId: "wh_1"}
+		if err := proxy.startCursor(path, "", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		notifications, err := proxy.Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, notif := range notifications {
+			proxy.httpProxy(notif)
+		}
+	}
+
+	forward()
+	// received while the listener was stopped, in the same second as the cursor and after it
+	mockClient.notifications = append(mockClient.notifications,
+		chunkify.Notification{ID: "notf_3", Event: "job.completed", CreatedAt: created},
+		chunkify.Notification{ID: "notf_4", Event: "job.completed", CreatedAt: created.Add(time.Second)},
+	)
+	forward()
+
+	want := []string{"notf_1", "notf_2", "notf_3", "notf_4"}
+	if fmt.Sprint(forwarded) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, forwarded)
+	}
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// DeadLetter is a notification listen couldn't deliver after all its retries.
+// Dead letters are appended to an NDJSON file, one per line, and sent again with listen --redeliver
+type DeadLetter struct {
+	NotificationID string    `json:"notification_id"`
+	Event          string    `json:"event"`
+	ObjectID       string    `json:"object_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	Payload        string    `json:"payload"`
+	URL            string    `json:"url"` // URL the notification failed to be delivered to
+	Attempts       int       `json:"attempts"`
+	StatusCode     int       `json:"status_code,omitempty"` // status code of the last attempt, 0 if the request failed
+	Error          string    `json:"error"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+func newDeadLetter(notif chunkify.Notification, localUrl string, attempts int, statusCode int, err error, failedAt time.Time) DeadLetter {
+	return DeadLetter{
+		NotificationID: notif.ID,
+		Event:          string(notif.Event),
+		ObjectID:       notif.ObjectID,
+		CreatedAt:      notif.CreatedAt,
+		Payload:        notif.Payload,
+		URL:            localUrl,
+		Attempts:       attempts,
+		StatusCode:     statusCode,
+		Error:          err.Error(),
+		FailedAt:       failedAt,
+	}
+}
+
+// Notification returns the notification to deliver again
+func (d DeadLetter) Notification() chunkify.Notification {
+	return chunkify.Notification{
+		ID:        d.NotificationID,
+		Event:     chunkify.NotificationEvent(d.Event),
+		ObjectID:  d.ObjectID,
+		CreatedAt: d.CreatedAt,
+		Payload:   d.Payload,
+	}
+}
+
+// AppendDeadLetter adds the dead letter at the end of the file at path, creating it and its directory if needed
+func AppendDeadLetter(path string, deadLetter DeadLetter) error {
+	data, err := json.Marshal(deadLetter)
+	if err != nil {
+		return fmt.Errorf("encode dead letter: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create dead letter dir: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open dead letter file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write dead letter: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadDeadLetters returns the dead letters of the file at path, in the order they were written
+func LoadDeadLetters(path string) ([]DeadLetter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open dead letter file: %w", err)
+	}
+	defer f.Close()
+
+	deadLetters := []DeadLetter{}
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read dead letter file: %w", err)
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			deadLetter := DeadLetter{}
+			if err := json.Unmarshal(data, &deadLetter); err != nil {
+				return nil, fmt.Errorf("decode dead letter at line %d: %w", line, err)
+			}
+			deadLetters = append(deadLetters, deadLetter)
+		}
+		if errors.Is(err, io.EOF) {
+			return deadLetters, nil
+		}
+	}
+}
+
+// SaveDeadLetters replaces the file at path with the dead letters, or removes it when there is none left.
+// The file is replaced atomically so a redeliver killed while saving doesn't lose the dead letters
+func SaveDeadLetters(path string, deadLetters []DeadLetter) error {
+	if len(deadLetters) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove dead letter file: %w", err)
+		}
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	for _, deadLetter := range deadLetters {
+		data, err := json.Marshal(deadLetter)
+		if err != nil {
+			return fmt.Errorf("encode dead letter: %w", err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write dead letter file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write dead letter file: %w", err)
+	}
+	return nil
+}
+
+// redeliver sends the dead letters of the file at path once, in order, to localUrl if set or else to the URL
+// they failed to be delivered to. The delivered ones are removed from the file, the others are kept with their last error
+func redeliver(w io.Writer, path string, localUrl string, webhookSecret string) error {
+	deadLetters, err := LoadDeadLetters(path)
+	if err != nil {
+		return err
+	}
+	if len(deadLetters) == 0 {
+		fmt.Fprintf(w, "%sNo dead letter in %s\n", indent, path)
+		return SaveDeadLetters(path, nil)
+	}
+
+	if localUrl == "" {
+		for _, deadLetter := range deadLetters {
+			if deadLetter.URL == "" {
+				return fmt.Errorf("dead letter %s has no URL, use --forward-to", deadLetter.NotificationID)
+			}
+		}
+	}
+
+	failed := []DeadLetter{}
+	for _, deadLetter := range deadLetters {
+		notif := deadLetter.Notification()
+		target, shown := localUrl, ""
+		if target == "" {
+			target, shown = deadLetter.URL, deadLetter.URL
+		}
+
+		statusCode, err := deliver(w, target, webhookSecret, notif, shown)
+		if err == nil {
+			continue
+		}
+		failed = append(failed, newDeadLetter(notif, deadLetter.URL, deadLetter.Attempts+1, statusCode, err, time.Now()))
+	}
+
+	if err := SaveDeadLetters(path, failed); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s%d redelivered, %d failed\n", indent, len(deadLetters)-len(failed), len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("%d notifications couldn't be delivered, they are kept in %s", len(failed), path)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestDeadLetters_AppendLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadletter.ndjson")
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"notf_1", "notf_2"} {
+		notif := chunkify.Notification{ID: id, Event: "job.completed", ObjectID: "job_1", CreatedAt: created, Payload: `{"event":"job.completed"}`}
+		if err := AppendDeadLetter(path, newDeadLetter(notif, "http://localhost:3000", 8, 500, errors.New("boom"), created)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadLetters, err := LoadDeadLetters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 2 || deadLetters[1].NotificationID != "notf_2" {
+		t.Fatalf("Expected notf_1 and notf_2, got %+v", deadLetters)
+	}
+	notif := deadLetters[0].Notification()
+	if notif.ID != "notf_1" || notif.Event != "job.completed" || notif.Payload != `{"event":"job.completed"}` || !notif.CreatedAt.Equal(created) {
+		t.Errorf("Unexpected notification %+v", notif)
+	}
+
+	if err := SaveDeadLetters(path, deadLetters[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if deadLetters, _ = LoadDeadLetters(path); len(deadLetters) != 1 {
+		t.Errorf("Expected 1 dead letter left, got %+v", deadLetters)
+	}
+
+	if err := SaveDeadLetters(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the empty dead letter file to be removed, got %v", err)
+	}
+}
+
+func TestLoadDeadLetters_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadletter.ndjson")
+	os.WriteFile(path, []byte("{\"notification_id\":\"notf_1\"}\nnot json\n"), 0644)
+
+	if _, err := LoadDeadLetters(path); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected a decode error at line 2, got %v", err)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("webhook-id") == "notf_2" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "deadletter.ndjson")
+	for _, id := range []string{"notf_1", "notf_2", "notf_3"} {
+		AppendDeadLetter(path, DeadLetter{NotificationID: id, Event: "job.completed", Attempts: 8, Error: "boom"})
+	}
+
+	out := &bytes.Buffer{}
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret"))
+	if err := redeliver(out, path, server.URL, secret); err == nil {
+		t.Error("Expected an error for the failed redelivery")
+	}
+	if !strings.Contains(out.String(), "2 redelivered, 1 failed") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	deadLetters, err := LoadDeadLetters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 1 || deadLetters[0].NotificationID != "notf_2" || deadLetters[0].Attempts != 9 || deadLetters[0].StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected notf_2 to be kept, got %+v", deadLetters)
+	}
+}
+
+func TestRedeliver_DeadLetterURL(t *testing.T) {
+	received := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "deadletter.ndjson")
+	AppendDeadLetter(path, DeadLetter{NotificationID: "notf_1", Event: "job.completed", URL: server.URL + "/jobs"})
+	AppendDeadLetter(path, DeadLetter{NotificationID: "notf_2", Event: "upload.completed", URL: server.URL + "/uploads"})
+
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret"))
+	if err := redeliver(&bytes.Buffer{}, path, "", secret); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/jobs", "/uploads"}; !slices.Equal(received, want) {
+		t.Errorf("Expected the dead letters to be sent to %v, got %v", want, received)
+	}
+
+	AppendDeadLetter(path, DeadLetter{NotificationID: "notf_3", Event: "job.completed"})
+	if err := redeliver(&bytes.Buffer{}, path, "", secret); err == nil {
+		t.Error("Expected an error for a dead letter without URL")
+	}
+}
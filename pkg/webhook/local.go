@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
+	"github.com/spf13/cobra"
+)
+
+// NewWebhookCommand creates and configures the webhook root command, the local tools
+// to develop a webhook handler. They don't call the API, so no project token is needed
+func NewWebhookCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "webhook",
+			Short: "Verify and trigger webhook notifications locally",
+			Long: `Verify and trigger webhook notifications locally, without calling the API
+
+Examples:
+
+chunkify webhook trigger job.failed --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>
+chunkify webhook verify --secret <ws_secret> --id notf_2G6MJiNz71bHQGNzGwKx5cJwPFS --timestamp 1700000000 --signature v1,<base64> < payload.json
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newWebhookVerifyCommand())
+	cmd.Command.AddCommand(newWebhookTriggerCommand())
+
+	return cmd
+}
+
+// VerifyOptions holds the headers and secret of the webhook verify command
+type VerifyOptions struct {
+	Secret    string        // webhook secret key
+	ID        string        // webhook-id header
+	Timestamp string        // webhook-timestamp header
+	Signature string        // webhook-signature header, space separated signatures
+	Tolerance time.Duration // maximum age of the timestamp, 0 disables the check
+}
+
+func newWebhookVerifyCommand() *cobra.Command {
+	opts := VerifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the signature of a notification payload read from stdin",
+		Long: `Verify the signature of a notification payload read from stdin
+
+The payload is verified like the Standard Webhooks libraries do: the webhook-signature header may hold
+several space separated signatures, one of them must match, and the timestamp must be within the tolerance.
+The command fails if the signature is invalid.`,
+		Example:      "chunkify webhook verify --secret <ws_secret> --id notf_2G6MJiNz71bHQGNzGwKx5cJwPFS --timestamp 1700000000 --signature v1,<base64> < payload.json",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verifyPayload(cmd.InOrStdin(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Secret, "secret", "", "The webhook secret key, with or without its whsec_ prefix")
+	cmd.Flags().StringVar(&opts.ID, "id", "", "The webhook-id header")
+	cmd.Flags().StringVar(&opts.Timestamp, "timestamp", "", "The webhook-timestamp header, in seconds since epoch")
+	cmd.Flags().StringVar(&opts.Signature, "signature", "", "The webhook-signature header")
+	cmd.Flags().DurationVar(&opts.Tolerance, "tolerance", verify.DefaultTolerance, "Maximum difference between the timestamp and now. 0 disables the check, e.g. to verify a notification from your logs")
+	cmd.MarkFlagRequired("secret")
+	cmd.MarkFlagRequired("id")
+	cmd.MarkFlagRequired("timestamp")
+	cmd.MarkFlagRequired("signature")
+
+	return cmd
+}
+
+// verifyPayload verifies the signature of the payload read from r, as is
+func verifyPayload(r io.Reader, w io.Writer, opts VerifyOptions) error {
+	verifier, err := verify.New(opts.Secret)
+	if err != nil {
+		return err
+	}
+	verifier.Tolerance = opts.Tolerance
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error reading the payload: %w", err)
+	}
+
+	if err := verifier.VerifySignature(opts.ID, opts.Timestamp, opts.Signature, payload); err != nil {
+		return fmt.Errorf("invalid notification %s: %w", opts.ID, err)
+	}
+
+	fmt.Fprintf(w, "%sSignature of %s verified\n", indent, opts.ID)
+	return nil
+}
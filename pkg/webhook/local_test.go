@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
+)
+
+func TestVerifyPayload(t *testing.T) {
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret"))
+	payload := `{"event":"job.completed","data":{"id":"job_1"}}` + "\n"
+	now := time.Now()
+
+	opts := VerifyOptions{
+		Secret:    secret,
+		ID:        "notf_1",
+		Timestamp: strconv.FormatInt(now.Unix(), 10),
+		Signature: "v1,b3RoZXI= " + generateSignature("notf_1", now, payload, secret),
+		Tolerance: verify.DefaultTolerance,
+	}
+
+	out := &bytes.Buffer{}
+	if err := verifyPayload(strings.NewReader(payload), out, opts); err != nil {
+		t.Fatalf("Expected the payload signed by listen to be verified, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Signature of notf_1 verified") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	// the payload is verified as is, a trimmed one doesn't match
+	if err := verifyPayload(strings.NewReader(strings.TrimSpace(payload)), out, opts); !errors.Is(err, verify.ErrNoSignature) {
+		t.Errorf("Expected %v, got %v", verify.ErrNoSignature, err)
+	}
+
+	old := now.Add(-time.Hour)
+	opts.Timestamp = strconv.FormatInt(old.Unix(), 10)
+	opts.Signature = generateSignature("notf_1", old, payload, secret)
+	if err := verifyPayload(strings.NewReader(payload), out, opts); !errors.Is(err, verify.ErrMessageTooOld) {
+		t.Errorf("Expected %v, got %v", verify.ErrMessageTooOld, err)
+	}
+
+	opts.Tolerance = 0
+	if err := verifyPayload(strings.NewReader(payload), out, opts); err != nil {
+		t.Errorf("Expected no timestamp check without tolerance, got %v", err)
+	}
+
+	opts.Secret = "whsec_not base64"
+	if err := verifyPayload(strings.NewReader(payload), out, opts); err == nil {
+		t.Error("Expected an error for an invalid secret")
+	}
+}
@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
+	"github.com/spf13/cobra"
+)
+
+// NotificationFilters holds the filters of the notifications list command
+type NotificationFilters struct {
+	Events        []string // events of the notifications
+	WebhookID     string   // webhook which received the notifications
+	ObjectID      string   // job or upload which triggered the notifications
+	CreatedAfter  string   // date, RFC3339 time or duration ago
+	CreatedBefore string   // date, RFC3339 time or duration ago
+	Limit         int64    // maximum number of notifications listed, 0 lists all
+}
+
+// NewNotificationsCommand creates and configures a new notifications root command
+func NewNotificationsCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "notifications",
+			Short: "List and replay webhook notifications",
+			Long: `List the notifications sent to the webhooks and replay them to a local URL
+
+Examples:
+
+chunkify notifications list --events job.failed --created-after 24h
+chunkify notifications list --object job_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify notifications replay notf_2G6MJiNz71bHQGNzGwKx5cJwPFS --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newNotificationsListCommand(cfg))
+	cmd.Command.AddCommand(newNotificationsReplayCommand(cfg))
+
+	return cmd
+}
+
+func newNotificationsListCommand(cfg *config.Config) *cobra.Command {
+	filters := NotificationFilters{}
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the notifications, most recent first",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params, err := filters.Params()
+			if err != nil {
+				return err
+			}
+
+			client := &ChunkifyClient{Client: cfg.Client}
+			notifications, err := ListNotifications(cmd.Context(), client, params, filters.Limit)
+			if err != nil {
+				return fmt.Errorf("error listing notifications: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, notifications)
+			}
+			return printNotifications(os.Stdout, notifications)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&filters.Events, "events", nil, "Only list the notifications of the given events: "+strings.Join(allEvents, ", "))
+	cmd.Flags().StringVar(&filters.WebhookID, "webhook", "", "Only list the notifications sent to the given webhook ID")
+	cmd.Flags().StringVar(&filters.ObjectID, "object", "", "Only list the notifications of the given job or upload ID")
+	cmd.Flags().StringVar(&filters.CreatedAfter, "created-after", "", "Only list the notifications created after the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().StringVar(&filters.CreatedBefore, "created-before", "", "Only list the notifications created before the given date (2006-01-02), time (RFC3339) or duration ago (24h)")
+	cmd.Flags().Int64Var(&filters.Limit, "limit", 0, "Maximum number of notifications to list. All the notifications are listed by default")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newNotificationsReplayCommand(cfg *config.Config) *cobra.Command {
+	var localUrl, webhookSecret string
+
+	cmd := &cobra.Command{
+		Use:   "replay <notf_id>...",
+		Short: "Send past notifications again to a local URL",
+		Long: `Send past notifications again to a local URL
+
+The stored payload is signed with the webhook secret and sent like chunkify listen does.
+The command fails if the URL doesn't respond with a 2xx status code.`,
+		Example: "chunkify notifications replay notf_2G6MJiNz71bHQGNzGwKx5cJwPFS --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>",
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := verify.ParseSecret(webhookSecret); err != nil {
+				return fmt.Errorf("invalid --webhook-secret: %w", err)
+			}
+			return nil
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			return replayNotifications(cmd.Context(), client, os.Stdout, args, localUrl, webhookSecret)
+		},
+	}
+
+	cmd.Flags().StringVar(&localUrl, "forward-to", "", "The URL to send the notifications to")
+	cmd.Flags().StringVar(&webhookSecret, "webhook-secret", "", "Use your project's webhook secret key to sign the notifications")
+	cmd.MarkFlagRequired("forward-to")
+	cmd.MarkFlagRequired("webhook-secret")
+
+	return cmd
+}
+
+// replayNotifications sends the notifications again to localUrl, in the given order
+func replayNotifications(ctx context.Context, client ChunkifyClientInterface, w io.Writer, ids []string, localUrl string, webhookSecret string) error {
+	errs := []error{}
+	for _, id := range ids {
+		notif, err := client.NotificationGet(ctx, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error getting notification %s: %w", id, err))
+			continue
+		}
+
+		statusCode, err := forwardNotification(localUrl, webhookSecret, *notif)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error replaying notification %s: %w", id, err))
+			continue
+		}
+
+		printForwarded(w, statusCode, *notif, "")
+		if statusCode < 200 || statusCode >= 300 {
+			errs = append(errs, fmt.Errorf("notification %s: %s responded with %d %s", id, localUrl, statusCode, http.StatusText(statusCode)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Params converts the filters to the list params, sorted by creation date, most recent first
+func (f NotificationFilters) Params() (chunkify.NotificationListParams, error) {
+	params := chunkify.NotificationListParams{
+		Created: chunkify.NotificationListParamsCreated{Sort: "desc"},
+	}
+
+	if err := validateEvents(f.Events); err != nil {
+		return params, err
+	}
+	params.Events = f.Events
+
+	if f.WebhookID != "" {
+		params.WebhookID = chunkify.String(f.WebhookID)
+	}
+	if f.ObjectID != "" {
+		params.ObjectID = chunkify.String(f.ObjectID)
+	}
+
+	var err error
+	params.Created.Gte, params.Created.Lte, err = formatter.ParseCreated(f.CreatedAfter, f.CreatedBefore, time.Now())
+	if err != nil {
+		return params, err
+	}
+
+	if f.Limit < 0 {
+		return params, fmt.Errorf("--limit must be positive")
+	}
+
+	return params, nil
+}
+
+// ListNotifications returns the notifications matching params, fetching the pages until limit is reached.
+// A limit of 0 returns all the notifications
+func ListNotifications(ctx context.Context, client ChunkifyClientInterface, params chunkify.NotificationListParams, limit int64) ([]chunkify.Notification, error) {
+	return formatter.Paginate(limit, func(offset int64, size int64) ([]chunkify.Notification, int64, error) {
+		params.Limit = chunkify.Int(size)
+		params.Offset = chunkify.Int(offset)
+		// the total isn't returned, the last page is the one that isn't full
+		page, err := client.NotificationList(ctx, params)
+		return page, -1, err
+	})
+}
+
+func printNotifications(w io.Writer, notifications []chunkify.Notification) error {
+	if len(notifications) == 0 {
+		fmt.Fprintln(w, indent+"No notification found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tCREATED\tEVENT\tOBJECT\tWEBHOOK\tRESPONSE")
+	for _, n := range notifications {
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\t%s\n",
+			indent,
+			n.ID,
+			n.CreatedAt.Local().Format(time.DateTime),
+			n.Event,
+			n.ObjectID,
+			n.Webhook.ID,
+			responseStatus(n.ResponseStatusCode))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(notifications))
+	return nil
+}
+
+// responseStatus formats the status code returned by the webhook, if any
+func responseStatus(statusCode int64) string {
+	if statusCode == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d %s", statusCode, http.StatusText(int(statusCode)))
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestListNotifications_Pagination(t *testing.T) {
+	client := &MockChunkifyClient{}
+	for i := range 230 {
+		client.notifications = append(client.notifications, chunkify.Notification{ID: fmt.Sprintf("notf_%d", i)})
+	}
+
+	notifications, err := ListNotifications(context.Background(), client, chunkify.NotificationListParams{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 230 || notifications[229].ID != "notf_229" {
+		t.Errorf("expected all the notifications, got %d", len(notifications))
+	}
+
+	notifications, err = ListNotifications(context.Background(), client, chunkify.NotificationListParams{}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 20 {
+		t.Errorf("expected 20 notifications, got %d", len(notifications))
+	}
+}
+
+func TestNotificationFilters_Params(t *testing.T) {
+	params, err := NotificationFilters{Events: []string{"job.failed"}, WebhookID: "wh_1", ObjectID: "job_1", CreatedAfter: "24h"}.Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.WebhookID.Value != "wh_1" || params.ObjectID.Value != "job_1" || params.Events[0] != "job.failed" || params.Created.Gte.Value == 0 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+
+	if _, err := (NotificationFilters{Events: []string{"job.started"}}).Params(); err == nil {
+		t.Error("expected an error for an invalid event")
+	}
+	if _, err := (NotificationFilters{CreatedBefore: "yesterday"}).Params(); err == nil || !strings.Contains(err.Error(), "--created-before") {
+		t.Errorf("expected an error for an invalid date, got %v", err)
+	}
+}
+
+func TestReplayNotifications(t *testing.T) {
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("test-secret"))
+	payload := `{"event":"job.completed"}`
+
+	var received []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf("expected the stored payload, got %s", body)
+		}
+		received = append(received, r)
+		if r.Header.Get("webhook-id") == "notf_2" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &MockChunkifyClient{notifications: []chunkify.Notification{
+		{ID: "notf_1", Event: "job.completed", ObjectID: "job_1", Payload: payload},
+		{ID: "notf_2", Event: "job.completed", ObjectID: "job_2", Payload: payload},
+	}}
+
+	var buf bytes.Buffer
+	err := replayNotifications(context.Background(), client, &buf, []string{"notf_1", "notf_2", "notf_3"}, server.URL, secret)
+	if err == nil || !strings.Contains(err.Error(), "notification notf_2") || !strings.Contains(err.Error(), "notification notf_3") {
+		t.Errorf("expected errors for notf_2 and notf_3, got %v", err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(received))
+	}
+
+	// the payload is signed like the listen command does
+	timestamp, _ := strconv.ParseInt(received[0].Header.Get("webhook-timestamp"), 10, 64)
+	want := generateSignature("notf_1", time.Unix(timestamp, 0), payload, secret)
+	if received[0].Header.Get("webhook-signature") != want {
+		t.Errorf("expected signature %s, got %s", want, received[0].Header.Get("webhook-signature"))
+	}
+	if !strings.Contains(buf.String(), "[200 OK] notf_1 job.completed (job_1)") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestNotificationsReplayCommand_Secret(t *testing.T) {
+	for _, secret := range []string{"whsec_", "whsec_not base64"} {
+		cmd := newNotificationsReplayCommand(nil)
+		cmd.Flags().Set("webhook-secret", secret)
+		if err := cmd.PreRunE(cmd, []string{"notf_1"}); err == nil || !strings.Contains(err.Error(), "invalid --webhook-secret") {
+			t.Errorf("expected an invalid --webhook-secret error for %q, got %v", secret, err)
+		}
+	}
+}
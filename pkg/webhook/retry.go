@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+// retrySchedule is the delay before each retry of a failed delivery, the schedule of the Standard Webhooks senders.
+// A notification is attempted once, then retried len(retrySchedule) times before it's written to the dead letter file
+var retrySchedule = []time.Duration{
+	5 * time.Second,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	5 * time.Hour,
+	10 * time.Hour,
+	10 * time.Hour,
+}
+
+// retry is a failed delivery waiting for its next attempt
+type retry struct {
+	notif      chunkify.Notification
+	route      *Route    // route the notification is delivered to
+	attempts   int       // failed attempts so far
+	statusCode int       // status code of the last attempt, 0 if the request failed
+	err        error     // error of the last attempt
+	next       time.Time // when the next attempt is due
+}
+
+// deliver forwards the notification to localUrl and prints the response, followed by target if not empty.
+// Request errors and non 2xx responses are returned as errors, with the status code if any
+func deliver(w io.Writer, localUrl string, webhookSecret string, notif chunkify.Notification, target string) (int, error) {
+	statusCode, err := forwardNotification(localUrl, webhookSecret, notif)
+	if err != nil {
+		fmt.Fprintf(w, "  [Request error] %s %s (%s)%s: %s\n", notif.ID, notif.Event, notif.ObjectID, targetSuffix(target), err)
+		return 0, err
+	}
+
+	printForwarded(w, statusCode, notif, target)
+	if statusCode < 200 || statusCode >= 300 {
+		return statusCode, fmt.Errorf("%s responded with %d %s", localUrl, statusCode, http.StatusText(statusCode))
+	}
+	return statusCode, nil
+}
+
+// targetSuffix shows where a notification is delivered, when listen has several routes
+func targetSuffix(target string) string {
+	if target == "" {
+		return ""
+	}
+	return " -> " + target
+}
+
+// target returns the URL of route to print, only when there are several routes
+func (r *WebhookProxy) target(route *Route) string {
+	if len(r.routes) < 2 {
+		return ""
+	}
+	return route.URL
+}
+
+// attempt delivers the notification to its route, and schedules a retry if it fails
+func (r *WebhookProxy) attempt(d retry, now time.Time) {
+	statusCode, err := deliver(os.Stdout, d.route.URL, r.webhookSecret, d.notif, r.target(d.route))
+	if err == nil {
+		d.route.Delivered++
+		return
+	}
+
+	d.attempts++
+	d.statusCode, d.err = statusCode, err
+	r.scheduleRetry(d, now)
+}
+
+// scheduleRetry queues a failed delivery for its next attempt, or writes it
+// to the dead letter file when all the retries are exhausted
+func (r *WebhookProxy) scheduleRetry(d retry, now time.Time) {
+	if d.attempts > len(retrySchedule) {
+		r.deadLetter(d, now)
+		return
+	}
+
+	d.route.Retries++
+	d.next = now.Add(retrySchedule[d.attempts-1])
+	r.retries = append(r.retries, d)
+	fmt.Printf("  [Retry %d/%d] %s %s (%s)%s at %s\n",
+		d.attempts,
+		len(retrySchedule),
+		d.notif.ID,
+		d.notif.Event,
+		d.notif.ObjectID,
+		targetSuffix(r.target(d.route)),
+		d.next.Local().Format(time.TimeOnly))
+}
+
+// retryDue attempts again the deliveries due at now, in the order they failed
+func (r *WebhookProxy) retryDue(now time.Time) {
+	pending := r.retries
+	r.retries = nil
+
+	for _, d := range pending {
+		if now.Before(d.next) {
+			r.retries = append(r.retries, d)
+			continue
+		}
+		r.attempt(d, now)
+	}
+}
+
+// flushRetries writes the deliveries still waiting for a retry to the dead letter file, when listen stops
+func (r *WebhookProxy) flushRetries(now time.Time) {
+	for _, d := range r.retries {
+		r.deadLetter(d, now)
+	}
+	r.retries = nil
+}
+
+// deadLetter gives up on a delivery and appends it to the dead letter file, if any
+func (r *WebhookProxy) deadLetter(d retry, now time.Time) {
+	d.route.DeadLetters++
+	suffix := targetSuffix(r.target(d.route))
+
+	if r.deadLetterFile == "" {
+		fmt.Printf("  [Dead letter] %s %s (%s)%s after %d attempts: %s\n", d.notif.ID, d.notif.Event, d.notif.ObjectID, suffix, d.attempts, d.err)
+		return
+	}
+
+	if err := AppendDeadLetter(r.deadLetterFile, newDeadLetter(d.notif, d.route.URL, d.attempts, d.statusCode, d.err, now)); err != nil {
+		fmt.Printf("Error writing the dead letter of %s: %s\n", d.notif.ID, err)
+		return
+	}
+	fmt.Printf("  [Dead letter] %s %s (%s)%s after %d attempts, written to %s\n", d.notif.ID, d.notif.Event, d.notif.ObjectID, suffix, d.attempts, r.deadLetterFile)
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestWebhookProxy_Retry(t *testing.T) {
+	var mu sync.Mutex
+	status, attempts := http.StatusServiceUnavailable, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	proxy := &WebhookProxy{
+		localUrl:      server.URL,
+		webhookSecret: "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret")),
+	}
+	notif := chunkify.Notification{ID: "notf_1", Event: "job.completed", CreatedAt: time.Now()}
+
+	proxy.httpProxy(notif)
+	if len(proxy.retries) != 1 || proxy.retries[0].attempts != 1 || proxy.retries[0].statusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected the failed delivery to be queued, got %+v", proxy.retries)
+	}
+	if proxy.Cursor.NotificationID != "notf_1" {
+		t.Errorf("Expected the cursor to move on, got %+v", proxy.Cursor)
+	}
+
+	// not due yet
+	now := time.Now()
+	proxy.retryDue(now)
+	if attempts != 1 || len(proxy.retries) != 1 {
+		t.Fatalf("Expected no retry before %s, got %d attempts", proxy.retries[0].next, attempts)
+	}
+
+	now = proxy.retries[0].next
+	proxy.retryDue(now)
+	if attempts != 2 || len(proxy.retries) != 1 || proxy.retries[0].attempts != 2 {
+		t.Fatalf("Expected a second failed attempt, got %d attempts and %+v", attempts, proxy.retries)
+	}
+	if want := now.Add(retrySchedule[1]); !proxy.retries[0].next.Equal(want) {
+		t.Errorf("Expected the next attempt at %s, got %s", want, proxy.retries[0].next)
+	}
+
+	status = http.StatusOK
+	proxy.retryDue(proxy.retries[0].next)
+	if attempts != 3 || len(proxy.retries) != 0 {
+		t.Errorf("Expected the delivery to succeed, got %d attempts and %+v", attempts, proxy.retries)
+	}
+}
+
+func TestWebhookProxy_Retry_DeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "deadletter.ndjson")
+	proxy := &WebhookProxy{
+		localUrl:       server.URL,
+		webhookSecret:  "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret")),
+		deadLetterFile: path,
+	}
+
+	proxy.httpProxy(chunkify.Notification{ID: "notf_1", Event: "job.completed", CreatedAt: time.Now()})
+	for range retrySchedule {
+		proxy.retryDue(proxy.retries[0].next)
+	}
+	if len(proxy.retries) != 0 {
+		t.Fatalf("Expected the retries to be exhausted, got %+v", proxy.retries)
+	}
+
+	proxy.httpProxy(chunkify.Notification{ID: "notf_2", Event: "job.failed", CreatedAt: time.Now().Add(time.Second)})
+	proxy.flushRetries(time.Now())
+
+	deadLetters, err := LoadDeadLetters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 2 {
+		t.Fatalf("Expected 2 dead letters, got %+v", deadLetters)
+	}
+	if d := deadLetters[0]; d.NotificationID != "notf_1" || d.Attempts != len(retrySchedule)+1 || d.StatusCode != http.StatusInternalServerError || d.Error == "" {
+		t.Errorf("Unexpected dead letter %+v", d)
+	}
+	if d := deadLetters[1]; d.NotificationID != "notf_2" || d.Attempts != 1 {
+		t.Errorf("Expected the pending retry to be dead lettered on stop, got %+v", d)
+	}
+}
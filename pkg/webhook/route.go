@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Route forwards the notifications whose event matches Pattern to URL, and counts its deliveries
+type Route struct {
+	Pattern     string // glob matched against the event, e.g. job.*
+	URL         string // local URL receiving the notifications
+	Delivered   int    // deliveries answered with a 2xx status code
+	Retries     int    // failed attempts, retried later
+	DeadLetters int    // deliveries given up and written to the dead letter file
+}
+
+// parseRoute parses a --route rule: <event glob>=<url>
+func parseRoute(rule string) (*Route, error) {
+	pattern, target, ok := strings.Cut(rule, "=")
+	if !ok || pattern == "" || target == "" {
+		return nil, fmt.Errorf("invalid --route %s, expected <event>=<url>, e.g. job.*=http://localhost:3000/jobs", rule)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid --route pattern %s: %w", pattern, err)
+	}
+	if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid --route %s, the URL must be an HTTP URL", rule)
+	}
+	return &Route{Pattern: pattern, URL: target}, nil
+}
+
+// newRoutes returns the routes of the --route rules, followed by the one of --forward-to which receives all the events
+func newRoutes(forwardTo string, rules []string) ([]*Route, error) {
+	routes := []*Route{}
+	for _, rule := range rules {
+		route, err := parseRoute(rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+
+	if forwardTo != "" {
+		routes = append(routes, &Route{Pattern: "*", URL: forwardTo})
+	}
+
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("--forward-to or --route is required")
+	}
+	return routes, nil
+}
+
+// Match returns true if the route receives the notifications of event
+func (r *Route) Match(event string) bool {
+	ok, _ := path.Match(r.Pattern, event)
+	return ok
+}
+
+func (r *Route) String() string {
+	return r.Pattern + "=" + r.URL
+}
+
+// routedEvents returns the events matched by at least one route, the ones the localdev webhook subscribes to.
+// A route matching none of the events is an error, it would never receive anything
+func routedEvents(routes []*Route, events []string) ([]string, error) {
+	routed := []string{}
+	for _, route := range routes {
+		matched := false
+		for _, event := range events {
+			if !route.Match(event) {
+				continue
+			}
+			matched = true
+			if !slices.Contains(routed, event) {
+				routed = append(routed, event)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("--route %s doesn't match any of the events: %s", route, strings.Join(events, ", "))
+		}
+	}
+
+	// keep the order of the events
+	slices.SortStableFunc(routed, func(a, b string) int {
+		return slices.Index(events, a) - slices.Index(events, b)
+	})
+	return routed, nil
+}
+
+func printRouteStats(w io.Writer, routes []*Route) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ROUTE\tDELIVERED\tRETRIES\tDEAD LETTERS")
+	for _, route := range routes {
+		fmt.Fprintf(tw, "%s%s\t%d\t%d\t%d\n", indent, route, route.Delivered, route.Retries, route.DeadLetters)
+	}
+	return tw.Flush()
+}
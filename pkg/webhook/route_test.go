@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestParseRoute(t *testing.T) {
+	route, err := parseRoute("job.*=http://localhost:3000/jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Pattern != "job.*" || route.URL != "http://localhost:3000/jobs" {
+		t.Errorf("Unexpected route %+v", route)
+	}
+	if !route.Match("job.completed") || !route.Match("job.failed") || route.Match("upload.completed") {
+		t.Errorf("Unexpected matching of %s", route)
+	}
+
+	for _, rule := range []string{"job.*", "=http://localhost:3000", "job.*=", "job.[=http://localhost:3000", "job.*=localhost:3000", "job.*=ftp://localhost"} {
+		if _, err := parseRoute(rule); err == nil {
+			t.Errorf("Expected an error for %q", rule)
+		}
+	}
+}
+
+func TestNewRoutes(t *testing.T) {
+	routes, err := newRoutes("http://localhost:5000", []string{"job.*=http://localhost:3000/jobs", "upload.completed=http://localhost:4000/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, route := range routes {
+		got = append(got, route.String())
+	}
+	want := []string{"job.*=http://localhost:3000/jobs", "upload.completed=http://localhost:4000/hook", "*=http://localhost:5000"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := newRoutes("", nil); err == nil {
+		t.Error("Expected an error without --forward-to nor --route")
+	}
+}
+
+func TestRoutedEvents(t *testing.T) {
+	events := []string{"job.completed", "job.failed", "upload.completed", "upload.failed", "upload.expired"}
+
+	routes, _ := newRoutes("", []string{"upload.completed=http://localhost:4000/hook", "job.*=http://localhost:3000/jobs"})
+	routed, err := routedEvents(routes, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"job.completed", "job.failed", "upload.completed"}; !slices.Equal(routed, want) {
+		t.Errorf("Expected %v, got %v", want, routed)
+	}
+
+	// the default events of listen
+	routes, _ = newRoutes("", []string{"job.cancelled=http://localhost:3000/cancelled"})
+	routed, err = routedEvents(routes, allEvents)
+	if err != nil || !slices.Equal(routed, []string{"job.cancelled"}) {
+		t.Errorf("Expected job.cancelled to be routed, got %v, %v", routed, err)
+	}
+
+	routes, _ = newRoutes("", []string{"job.*=http://localhost:3000/jobs"})
+	if _, err := routedEvents(routes, []string{"upload.completed"}); err == nil {
+		t.Error("Expected an error for a route matching none of the events")
+	}
+}
+
+func TestWebhookProxy_HttpProxy_Routes(t *testing.T) {
+	received := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path+" "+r.Header.Get("webhook-id"))
+		if r.URL.Path == "/uploads" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	routes, _ := newRoutes(server.URL+"/all", []string{"job.*=" + server.URL + "/jobs", "upload.completed=" + server.URL + "/uploads"})
+	proxy := &WebhookProxy{
+		webhookSecret: "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret")),
+		routes:        routes,
+	}
+
+	created := time.Now()
+	proxy.httpProxy(chunkify.Notification{ID: "notf_1", Event: "job.completed", CreatedAt: created})
+	proxy.httpProxy(chunkify.Notification{ID: "notf_2", Event: "upload.completed", CreatedAt: created})
+	proxy.httpProxy(chunkify.Notification{ID: "notf_3", Event: "upload.failed", CreatedAt: created})
+
+	want := []string{"/jobs notf_1", "/all notf_1", "/uploads notf_2", "/all notf_2", "/all notf_3"}
+	if !slices.Equal(received, want) {
+		t.Errorf("Expected %v, got %v", want, received)
+	}
+
+	jobs, uploads, all := routes[0], routes[1], routes[2]
+	if jobs.Delivered != 1 || uploads.Delivered != 0 || uploads.Retries != 1 || all.Delivered != 3 {
+		t.Errorf("Unexpected stats: jobs %+v, uploads %+v, all %+v", jobs, uploads, all)
+	}
+	if len(proxy.retries) != 1 || proxy.retries[0].route != uploads {
+		t.Errorf("Expected the upload delivery to be retried, got %+v", proxy.retries)
+	}
+
+	out := &bytes.Buffer{}
+	printRouteStats(out, routes)
+	if !strings.Contains(out.String(), "DEAD LETTERS") || !strings.Contains(out.String(), "upload.completed="+server.URL+"/uploads") {
+		t.Errorf("Unexpected stats output %q", out.String())
+	}
+}
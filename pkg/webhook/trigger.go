@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
+	"github.com/spf13/cobra"
+)
+
+// eventTemplates are the example payloads of the events, one <event>.json file each
+//
+//go:embed events/*.json
+var eventTemplates embed.FS
+
+// TriggerOptions holds the flags of the webhook trigger command
+type TriggerOptions struct {
+	LocalUrl      string   // URL receiving the notification
+	WebhookSecret string   // key used to sign the notification
+	Sets          []string // <path>=<value> overrides of the payload
+}
+
+func newWebhookTriggerCommand() *cobra.Command {
+	opts := TriggerOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "trigger <event>",
+		Short: "Send a synthetic notification to a local URL",
+		Long: `Send a synthetic notification to a local URL, to test your webhook handler without a real job or upload
+
+The payload is built from an example of the event, with a new notification ID and the current date.
+Override any field with --set <path>=<value>, the path is dotted, e.g. data.job.id or data.files.0.size.
+The value is used as JSON when valid, e.g. 100, true or {"key":"value"}, else as a string.
+The payload is signed with the webhook secret and sent like chunkify listen does, no API call is made.
+The command fails if the URL doesn't respond with a 2xx status code.
+
+Events: ` + strings.Join(allEvents, ", "),
+		Example: `chunkify webhook trigger job.failed --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>
+chunkify webhook trigger job.completed --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret> --set data.job.id=job_x --set 'data.job.metadata={"user":"1"}'`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(allEvents, args[0]) {
+				return fmt.Errorf("invalid event: %s. Valid events are %s", args[0], strings.Join(allEvents, ", "))
+			}
+			if _, err := verify.ParseSecret(opts.WebhookSecret); err != nil {
+				return fmt.Errorf("invalid --webhook-secret: %w", err)
+			}
+			return nil
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return triggerEvent(cmd.OutOrStdout(), args[0], opts, time.Now())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.LocalUrl, "forward-to", "", "The URL to send the notification to")
+	cmd.Flags().StringVar(&opts.WebhookSecret, "webhook-secret", "", "Use your project's webhook secret key to sign the notification")
+	cmd.Flags().StringArrayVar(&opts.Sets, "set", nil, "Override a field of the payload: <path>=<value>, e.g. data.job.id=job_x. Can be repeated")
+	cmd.MarkFlagRequired("forward-to")
+	cmd.MarkFlagRequired("webhook-secret")
+
+	return cmd
+}
+
+// triggerEvent builds a notification of event and sends it to the local URL
+func triggerEvent(w io.Writer, event string, opts TriggerOptions, now time.Time) error {
+	notif, err := syntheticNotification(event, opts.Sets, now)
+	if err != nil {
+		return err
+	}
+
+	if _, err := deliver(w, opts.LocalUrl, opts.WebhookSecret, notif, ""); err != nil {
+		return fmt.Errorf("error triggering %s: %w", event, err)
+	}
+	return nil
+}
+
+// syntheticNotification returns a notification of event built from its template, with a new ID,
+// the date set to now and the overrides applied
+func syntheticNotification(event string, sets []string, now time.Time) (chunkify.Notification, error) {
+	data, err := eventTemplates.ReadFile("events/" + event + ".json")
+	if err != nil {
+		return chunkify.Notification{}, fmt.Errorf("no template for event %s", event)
+	}
+
+	payload := map[string]any{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return chunkify.Notification{}, fmt.Errorf("invalid template for event %s: %w", event, err)
+	}
+
+	id, err := randomID("notf_")
+	if err != nil {
+		return chunkify.Notification{}, err
+	}
+	payload["id"] = id
+	payload["date"] = now.UTC().Format(time.RFC3339)
+
+	for _, set := range sets {
+		path, value, ok := strings.Cut(set, "=")
+		if !ok || path == "" {
+			return chunkify.Notification{}, fmt.Errorf("invalid --set %s, expected <path>=<value>", set)
+		}
+		if err := setPath(payload, strings.Split(path, "."), parseValue(value)); err != nil {
+			return chunkify.Notification{}, fmt.Errorf("invalid --set %s: %w", set, err)
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return chunkify.Notification{}, fmt.Errorf("error encoding the payload: %w", err)
+	}
+
+	// the id and event sent in the headers are the ones of the payload, even if overridden
+	notif := chunkify.Notification{
+		ID:        fmt.Sprint(payload["id"]),
+		Event:     chunkify.NotificationEvent(fmt.Sprint(payload["event"])),
+		CreatedAt: now,
+		Payload:   string(body),
+	}
+	for _, object := range []string{"job", "upload"} {
+		if id, ok := lookupPath(payload, []string{"data", object, "id"}).(string); ok {
+			notif.ObjectID = id
+			break
+		}
+	}
+	return notif, nil
+}
+
+// parseValue returns the JSON value of s if valid, else s as a string
+func parseValue(s string) any {
+	var value any
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		return s
+	}
+	return value
+}
+
+// setPath sets the value at the dotted path of v, creating the missing objects.
+// Array elements are set by index, within the array
+func setPath(v any, path []string, value any) error {
+	key := path[0]
+	switch node := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			node[key] = value
+			return nil
+		}
+		child, ok := node[key]
+		if !ok || child == nil {
+			child = map[string]any{}
+			node[key] = child
+		}
+		return setPath(child, path[1:], value)
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(node) {
+			return fmt.Errorf("%s is not an index of an array of %d elements", key, len(node))
+		}
+		if len(path) == 1 {
+			node[i] = value
+			return nil
+		}
+		return setPath(node[i], path[1:], value)
+	default:
+		return fmt.Errorf("%s can't be set on a %T", key, v)
+	}
+}
+
+// lookupPath returns the value at the dotted path of v, or nil if there is none
+func lookupPath(v any, path []string) any {
+	for _, key := range path {
+		node, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = node[key]
+	}
+	return v
+}
+
+// idChars are the characters of the Chunkify IDs
+const idChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// randomID returns an ID looking like the ones of Chunkify: the prefix followed by 27 alphanumeric characters
+func randomID(prefix string) (string, error) {
+	id := []byte(prefix)
+	for range 27 {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(idChars))))
+		if err != nil {
+			return "", fmt.Errorf("error generating an ID: %w", err)
+		}
+		id = append(id, idChars[n.Int64()])
+	}
+	return string(id), nil
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
+)
+
+func TestSyntheticNotification_Templates(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, event := range allEvents {
+		notif, err := syntheticNotification(event, nil, now)
+		if err != nil {
+			t.Fatalf("%s: %v", event, err)
+		}
+
+		payload := chunkify.UnwrapWebhookEvent{}
+		if err := json.Unmarshal([]byte(notif.Payload), &payload); err != nil {
+			t.Fatalf("%s: invalid payload: %v", event, err)
+		}
+		if string(payload.Event) != event || string(notif.Event) != event {
+			t.Errorf("%s: unexpected event %s", event, payload.Event)
+		}
+		if payload.ID != notif.ID || !strings.HasPrefix(notif.ID, "notf_") || len(notif.ID) != len("notf_")+27 {
+			t.Errorf("%s: unexpected ID %s", event, notif.ID)
+		}
+		if !payload.Date.Equal(now) {
+			t.Errorf("%s: expected the date %s, got %s", event, now, payload.Date)
+		}
+		if notif.ObjectID == "" || (notif.ObjectID != payload.Data.Job.ID && notif.ObjectID != payload.Data.Upload.ID) {
+			t.Errorf("%s: unexpected object ID %q", event, notif.ObjectID)
+		}
+	}
+}
+
+func TestSyntheticNotification_Set(t *testing.T) {
+	notif, err := syntheticNotification("job.completed", []string{
+		"data.job.id=job_x",
+		"data.job.billable_time=60",
+		`data.job.metadata={"user":"1"}`,
+		"data.files.0.size=42",
+		"data.extra.nested=true",
+	}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := chunkify.UnwrapWebhookEvent{}
+	if err := json.Unmarshal([]byte(notif.Payload), &payload); err != nil {
+		t.Fatal(err)
+	}
+	job := payload.Data.Job
+	if job.ID != "job_x" || notif.ObjectID != "job_x" || job.BillableTime != 60 || job.Metadata["user"] != "1" {
+		t.Errorf("Unexpected job %+v", job)
+	}
+	if payload.Data.Files[0].Size != 42 {
+		t.Errorf("Expected the file size to be 42, got %d", payload.Data.Files[0].Size)
+	}
+	if !strings.Contains(notif.Payload, `"extra":{"nested":true}`) {
+		t.Errorf("Expected the missing objects to be created, got %s", notif.Payload)
+	}
+
+	for _, set := range []string{"data.job.id", "=job_x", "data.files.1.size=42", "data.files.x=1", "event.name=x"} {
+		if _, err := syntheticNotification("job.completed", []string{set}, time.Now()); err == nil {
+			t.Errorf("Expected an error for --set %s", set)
+		}
+	}
+}
+
+func TestTriggerEvent(t *testing.T) {
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("secret"))
+	verifier, err := verify.New(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := http.StatusOK
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		if err := verifier.Verify(received, r.Header); err != nil {
+			t.Errorf("Expected a valid signature, got %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	opts := TriggerOptions{LocalUrl: server.URL, WebhookSecret: secret, Sets: []string{"data.upload.id=upl_x"}}
+	out := &bytes.Buffer{}
+	if err := triggerEvent(out, "upload.expired", opts, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(received), `"id":"upl_x"`) || !strings.Contains(out.String(), "[200 OK]") || !strings.Contains(out.String(), "upload.expired (upl_x)") {
+		t.Errorf("Unexpected payload %s or output %q", received, out.String())
+	}
+
+	status = http.StatusInternalServerError
+	if err := triggerEvent(out, "job.failed", opts, time.Now()); err == nil {
+		t.Error("Expected an error for a non 2xx response")
+	}
+}
@@ -0,0 +1,140 @@
+// Package verify verifies the webhook notifications sent by Chunkify, following the Standard Webhooks
+// specification: https://www.standardwebhooks.com
+//
+// A notification is signed with the webhook secret key over "id.timestamp.payload", and sent with the
+// webhook-id, webhook-timestamp and webhook-signature headers:
+//
+//	verifier, err := verify.New(os.Getenv("CHUNKIFY_WEBHOOK_SECRET"))
+//	if err != nil {
+//		return err
+//	}
+//	payload, _ := io.ReadAll(r.Body)
+//	if err := verifier.Verify(payload, r.Header); err != nil {
+//		http.Error(w, err.Error(), http.StatusUnauthorized)
+//		return
+//	}
+package verify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a signed notification
+const (
+	HeaderID        = "webhook-id"
+	HeaderTimestamp = "webhook-timestamp"
+	HeaderSignature = "webhook-signature"
+)
+
+// secretPrefix is the prefix of the webhook secret keys
+const secretPrefix = "whsec_"
+
+// signatureVersion is the only signature scheme of the specification, HMAC-SHA256
+const signatureVersion = "v1"
+
+// DefaultTolerance is the maximum difference between the timestamp of a notification and now,
+// which protects against replay attacks
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMissingHeaders   = errors.New("missing webhook-id, webhook-timestamp or webhook-signature header")
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrMessageTooOld    = errors.New("message timestamp too old")
+	ErrMessageTooNew    = errors.New("message timestamp too new")
+	ErrNoSignature      = errors.New("no matching signature found")
+)
+
+// Verifier verifies and signs notifications with a webhook secret key
+type Verifier struct {
+	key       []byte
+	Tolerance time.Duration    // maximum difference between the timestamp and now, 0 disables the check
+	now       func() time.Time // can be overridden in tests
+}
+
+// New returns a verifier for the secret key, with or without its whsec_ prefix
+func New(secret string) (*Verifier, error) {
+	key, err := ParseSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{key: key, Tolerance: DefaultTolerance, now: time.Now}, nil
+}
+
+// ParseSecret returns the key of a webhook secret: the base64 part after the optional whsec_ prefix
+func ParseSecret(secret string) ([]byte, error) {
+	encoded := strings.TrimPrefix(secret, secretPrefix)
+	if encoded == "" {
+		return nil, fmt.Errorf("empty webhook secret")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook secret: %w", err)
+	}
+	return key, nil
+}
+
+// Verify checks the signature of the payload with the webhook headers of the request
+func (v *Verifier) Verify(payload []byte, headers http.Header) error {
+	id, timestamp, signature := headers.Get(HeaderID), headers.Get(HeaderTimestamp), headers.Get(HeaderSignature)
+	if id == "" || timestamp == "" || signature == "" {
+		return ErrMissingHeaders
+	}
+	return v.VerifySignature(id, timestamp, signature, payload)
+}
+
+// VerifySignature checks that the timestamp is within the tolerance and that one of the
+// space separated signatures, e.g. "v1,<base64> v1,<base64>", is the one of the payload
+func (v *Verifier) VerifySignature(id string, timestamp string, signature string, payload []byte) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTimestamp, timestamp)
+	}
+	signedAt := time.Unix(unix, 0)
+
+	if v.Tolerance > 0 {
+		now := v.now()
+		if now.Sub(signedAt) > v.Tolerance {
+			return ErrMessageTooOld
+		}
+		if signedAt.Sub(now) > v.Tolerance {
+			return ErrMessageTooNew
+		}
+	}
+
+	expected := v.sign(id, unix, payload)
+	for _, versioned := range strings.Fields(signature) {
+		version, sig, ok := strings.Cut(versioned, ",")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrNoSignature
+}
+
+// Sign returns the webhook-signature header of the payload: "v1,<base64>"
+func (v *Verifier) Sign(id string, timestamp time.Time, payload []byte) string {
+	return signatureVersion + "," + base64.StdEncoding.EncodeToString(v.sign(id, timestamp.Unix(), payload))
+}
+
+// sign computes the HMAC-SHA256 of "id.timestamp.payload"
+func (v *Verifier) sign(id string, timestamp int64, payload []byte) []byte {
+	h := hmac.New(sha256.New, v.key)
+	fmt.Fprintf(h, "%s.%d.", id, timestamp)
+	h.Write(payload)
+	return h.Sum(nil)
+}
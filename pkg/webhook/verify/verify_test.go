@@ -0,0 +1,131 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// the example of the Standard Webhooks specification
+const (
+	testSecret    = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testID        = "msg_p5jXN8AQM9LWM0D4loKWxJek"
+	testTimestamp = "1614265330"
+	testPayload   = `{"test": 2432232314}`
+	testSignature = "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+)
+
+func newTestVerifier(t *testing.T) *Verifier {
+	v, err := New(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.now = func() time.Time { return time.Unix(1614265330, 0) }
+	return v
+}
+
+func TestParseSecret(t *testing.T) {
+	withPrefix, err := ParseSecret(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutPrefix, err := ParseSecret("MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(withPrefix) != string(withoutPrefix) {
+		t.Error("Expected the whsec_ prefix to be optional")
+	}
+
+	for _, secret := range []string{"", "whsec_", "whsec_not base64"} {
+		if _, err := ParseSecret(secret); err == nil {
+			t.Errorf("Expected an error for %q", secret)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	v := newTestVerifier(t)
+
+	if err := v.VerifySignature(testID, testTimestamp, testSignature, []byte(testPayload)); err != nil {
+		t.Errorf("Expected the signature of the specification to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		id        string
+		timestamp string
+		signature string
+		payload   string
+		err       error
+	}{
+		{name: "multiple signatures", signature: "v1,Zm9v v1a,ignored " + testSignature},
+		{name: "tampered payload", payload: `{"test": 1}`, err: ErrNoSignature},
+		{name: "other id", id: "msg_other", err: ErrNoSignature},
+		{name: "other version", signature: "v2,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", err: ErrNoSignature},
+		{name: "invalid base64", signature: "v1,not-base64", err: ErrNoSignature},
+		{name: "invalid timestamp", timestamp: "yesterday", err: ErrInvalidTimestamp},
+		{name: "too old", timestamp: "1614264000", err: ErrMessageTooOld},
+		{name: "too new", timestamp: "1614266000", err: ErrMessageTooNew},
+	}
+
+	for _, tt := range tests {
+		id, timestamp, signature, payload := testID, testTimestamp, testSignature, testPayload
+		if tt.id != "" {
+			id = tt.id
+		}
+		if tt.timestamp != "" {
+			timestamp = tt.timestamp
+		}
+		if tt.signature != "" {
+			signature = tt.signature
+		}
+		if tt.payload != "" {
+			payload = tt.payload
+		}
+
+		err := v.VerifySignature(id, timestamp, signature, []byte(payload))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestVerifySignature_NoTolerance(t *testing.T) {
+	v := newTestVerifier(t)
+	v.now = time.Now
+	if err := v.VerifySignature(testID, testTimestamp, testSignature, []byte(testPayload)); !errors.Is(err, ErrMessageTooOld) {
+		t.Errorf("Expected %v, got %v", ErrMessageTooOld, err)
+	}
+
+	v.Tolerance = 0
+	if err := v.VerifySignature(testID, testTimestamp, testSignature, []byte(testPayload)); err != nil {
+		t.Errorf("Expected the timestamp not to be checked, got %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	v, err := New("whsec_" + base64.StdEncoding.EncodeToString([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	payload := []byte(`{"event":"job.completed"}`)
+	headers := http.Header{}
+	headers.Set(HeaderID, "notf_1")
+	headers.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	headers.Set(HeaderSignature, v.Sign("notf_1", now, payload))
+
+	if err := v.Verify(payload, headers); err != nil {
+		t.Errorf("Expected a signed payload to be valid, got %v", err)
+	}
+
+	headers.Del(HeaderSignature)
+	if err := v.Verify(payload, headers); !errors.Is(err, ErrMissingHeaders) {
+		t.Errorf("Expected %v, got %v", ErrMissingHeaders, err)
+	}
+}
@@ -3,10 +3,8 @@ package webhook
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -18,6 +16,8 @@ import (
 
 	chunkify "github.com/chunkifydev/chunkify-go"
 	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/chunkifydev/cli/pkg/webhook/verify"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
@@ -30,7 +30,10 @@ type Command struct {
 
 type ChunkifyClientInterface interface {
 	NotificationList(ctx context.Context, params chunkify.NotificationListParams) ([]chunkify.Notification, error)
+	NotificationGet(ctx context.Context, notificationId string) (*chunkify.Notification, error)
+	WebhookList(ctx context.Context) ([]chunkify.Webhook, error)
 	WebhookCreate(ctx context.Context, params chunkify.WebhookNewParams) (*chunkify.Webhook, error)
+	WebhookUpdate(ctx context.Context, webhookId string, params chunkify.WebhookUpdateParams) error
 	WebhookDelete(ctx context.Context, webhookId string) error
 }
 
@@ -46,27 +49,106 @@ func (c *ChunkifyClient) NotificationList(ctx context.Context, params chunkify.N
 	return res.Data, nil
 }
 
+func (c *ChunkifyClient) NotificationGet(ctx context.Context, notificationId string) (*chunkify.Notification, error) {
+	return c.Client.Notifications.Get(ctx, notificationId)
+}
+
+func (c *ChunkifyClient) WebhookList(ctx context.Context) ([]chunkify.Webhook, error) {
+	res, err := c.Client.Webhooks.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
 func (c *ChunkifyClient) WebhookCreate(ctx context.Context, params chunkify.WebhookNewParams) (*chunkify.Webhook, error) {
 	return c.Client.Webhooks.New(ctx, params)
 }
 
+func (c *ChunkifyClient) WebhookUpdate(ctx context.Context, webhookId string, params chunkify.WebhookUpdateParams) error {
+	return c.Client.Webhooks.Update(ctx, webhookId, params)
+}
+
 func (c *ChunkifyClient) WebhookDelete(ctx context.Context, webhookId string) error {
 	return c.Client.Webhooks.Delete(ctx, webhookId)
 }
 
+// pollInterval is how often listen fetches the new notifications
+const pollInterval = 5 * time.Second
+
+// allEvents are the events a webhook can subscribe to. listen forwards all of them by default,
+// and webhook trigger can send each of them
+var allEvents = []string{
+	string(chunkify.NotificationEventJobCompleted),
+	string(chunkify.NotificationEventJobFailed),
+	string(chunkify.NotificationEventJobCancelled),
+	string(chunkify.NotificationEventUploadCompleted),
+	string(chunkify.NotificationEventUploadFailed),
+	string(chunkify.NotificationEventUploadExpired),
+}
+
 // NewCommand creates and configures a new notifications root command
 func NewCommand(config *config.Config) *Command {
-	var hostname string
+	var (
+		hostname    string
+		since       string
+		keepWebhook bool
+		redeliverTo string
+		routeRules  []string
+	)
 	req := WebhookProxy{}
 
 	cmd := &Command{
 		Config: config,
 		Command: &cobra.Command{
-			Use:     "listen",
-			Short:   "Forward webhook notifications to local HTTP URL",
-			Long:    "Forward webhook notifications to local HTTP URL for local development",
-			Example: "chunkify listen --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>",
+			Use:   "listen",
+			Short: "Forward webhook notifications to local HTTP URL",
+			Long: `Forward webhook notifications to local HTTP URL for local development
+
+Use --route to forward the events matching a glob to their own URL, e.g. job.*=http://localhost:3000/jobs.
+A notification is delivered to all the routes matching its event, the localdev webhook only subscribes to the routed events.
+
+The last forwarded notification is saved per profile and hostname, the next listen forwards the notifications received in the meantime.
+The localdev webhook is deleted on exit, so nothing is received while listen is stopped unless --keep-webhook is set.
+The notifications are listed by webhook: resuming and --since only forward the ones of the kept webhook.
+
+A failed delivery, or one answered with a non 2xx status code, is retried after 5s, 5m, 30m, 2h, 5h, 10h and 10h.
+When the retries are exhausted, or listen stops before, the notification is written to the --dead-letter file,
+by default a file per profile and hostname next to the saved notification. Deliver them again with --redeliver once your app is fixed.`,
+			Example: `chunkify listen --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret>
+chunkify listen --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret> --keep-webhook --since 2h
+chunkify listen --route 'job.*=http://localhost:3000/jobs' --route upload.completed=http://localhost:4000/hook --webhook-secret <ws_secret>
+chunkify listen --forward-to http://localhost:3000/webhooks/chunkify --webhook-secret <ws_secret> --redeliver deadletter.ndjson`,
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				if since != "" {
+					if _, err := formatter.ParseTime(since, time.Now()); err != nil {
+						return fmt.Errorf("invalid --since: %w", err)
+					}
+					// the notifications are listed by webhook, a new one has none before this listen
+					if !keepWebhook {
+						return fmt.Errorf("--since requires --keep-webhook, the notifications are only kept on the localdev webhook of a previous listen")
+					}
+				}
+				if redeliverTo != "" {
+					return nil
+				}
+
+				routes, err := newRoutes(req.localUrl, routeRules)
+				if err != nil {
+					return err
+				}
+				req.routes = routes
+				req.Events, err = routedEvents(routes, req.Events)
+				return err
+			},
 			Run: func(_ *cobra.Command, args []string) {
+				if redeliverTo != "" {
+					if err := redeliver(os.Stdout, redeliverTo, req.localUrl, req.webhookSecret); err != nil {
+						fmt.Printf("\nError redelivering the notifications: %s\n", err)
+					}
+					return
+				}
+
 				if hostname == "" {
 					hostname, _ = os.Hostname()
 					if hostname == "" {
@@ -77,24 +159,55 @@ func NewCommand(config *config.Config) *Command {
 				ctx, cancel := context.WithCancel(context.Background())
 				defer cancel()
 
-				webhookUrl := fmt.Sprintf("http://%s.chunkify.local", hostname)
+				webhookUrl := localDevWebhookURL(hostname)
 
 				req.Client = &ChunkifyClient{Client: config.Client}
 
-				webhook, err := req.createLocaldevWebhook(ctx, webhookUrl)
+				webhook, reused, err := req.localDevWebhook(ctx, webhookUrl)
 				if err != nil {
 					fmt.Printf("Error creating localdev webhook: %s\n", err)
 					return
 				}
 
-				defer req.deleteLocalDevWebhook(ctx, webhook.ID)
+				if !keepWebhook {
+					// ctx is cancelled on exit, the webhook must still be deleted
+					defer req.deleteLocalDevWebhook(context.Background(), webhook.ID)
+				}
 
 				req.WebhookId = webhook.ID
 
-				fmt.Printf("  [%s] Start forwarding to %s\n\n  Events:\n  - %s",
-					hostname,
-					req.localUrl,
-					strings.Join(req.Events, "\n  - "))
+				if req.deadLetterFile == "" {
+					if req.deadLetterFile, err = deadLetterPath(config.Profile, hostname); err != nil {
+						fmt.Printf("Error finding the dead letter file: %s\n", err)
+						return
+					}
+				}
+
+				path, err := cursorPath(config.Profile, hostname)
+				if err == nil {
+					err = req.startCursor(path, since, time.Now())
+				}
+				if err != nil {
+					fmt.Printf("Error loading the cursor: %s\n", err)
+					return
+				}
+
+				if len(routeRules) == 0 {
+					fmt.Printf("  [%s] Start forwarding to %s", hostname, req.localUrl)
+				} else {
+					routes := []string{}
+					for _, route := range req.routes {
+						routes = append(routes, route.String())
+					}
+					fmt.Printf("  [%s] Start forwarding\n\n  Routes:\n  - %s", hostname, strings.Join(routes, "\n  - "))
+				}
+				fmt.Printf("\n\n  Events:\n  - %s", strings.Join(req.Events, "\n  - "))
+
+				if reused && (since != "" || req.Cursor.NotificationID != "") {
+					fmt.Printf("\n\n  Catching up on the notifications since %s", req.Cursor.CreatedAt.Local().Format(time.DateTime))
+				} else if since != "" {
+					fmt.Printf("\n\n  Nothing to catch up on, the localdev webhook was just created. Keep it with --keep-webhook to catch up on the next listen")
+				}
 
 				fmt.Printf("\n\n  ────────────────────────────────────────────────\n\n")
 
@@ -112,75 +225,117 @@ func NewCommand(config *config.Config) *Command {
 				}()
 
 				req.Run(ctx)
+
+				fmt.Println()
+				printRouteStats(os.Stdout, req.routes)
 			},
 		},
 	}
 
-	allEvents := []string{
-		string(chunkify.NotificationEventJobCompleted),
-		string(chunkify.NotificationEventJobFailed),
-		string(chunkify.NotificationEventUploadCompleted),
-		string(chunkify.NotificationEventUploadFailed),
-		string(chunkify.NotificationEventUploadExpired),
-	}
-
 	cmd.Command.Flags().StringVar(&req.localUrl, "forward-to", "", "The URL to forward webhook notifications to")
-	cmd.Command.Flags().StringSliceVar(&req.Events, "events", allEvents, "Proxy all notifications with the given event. By default, all events are proxied. Event can be job.completed, job.failed, upload.completed, upload.failed, upload.expired")
+	cmd.Command.Flags().StringArrayVar(&routeRules, "route", nil, "Forward the notifications whose event matches a glob to a URL: <event>=<url>, e.g. job.*=http://localhost:3000/jobs. Can be repeated")
+	cmd.Command.Flags().StringSliceVar(&req.Events, "events", allEvents, "Proxy all notifications with the given event. By default, all events are proxied. Event can be "+strings.Join(allEvents, ", "))
 	cmd.Command.Flags().StringVar(&req.webhookSecret, "webhook-secret", "", "Use your project's webhook secret key to sign the notifications.")
 	cmd.Command.Flags().StringVar(&hostname, "hostname", "", "Use the given hostname for the localdev webhook. If not provided, we use the hostname of the machine. It's purely visual, it will just appear on Chunkify")
+	cmd.Command.Flags().StringVar(&since, "since", "", "Forward the notifications created since the given date (2006-01-02), time (RFC3339) or duration ago (2h), instead of resuming after the last forwarded one. Requires --keep-webhook, only the notifications of the kept webhook are listed")
+	cmd.Command.Flags().BoolVar(&keepWebhook, "keep-webhook", false, "Keep the localdev webhook on exit, so the notifications sent until the next listen are forwarded then")
+	cmd.Command.Flags().StringVar(&req.deadLetterFile, "dead-letter", "", "The NDJSON file where the notifications that couldn't be delivered are appended. Defaults to a file per profile and hostname in the user cache directory")
+	cmd.Command.Flags().StringVar(&redeliverTo, "redeliver", "", "Deliver again the notifications of the given dead letter file instead of listening. The delivered ones are removed from the file")
 
 	cmd.Command.MarkFlagRequired("webhook-secret")
+	cmd.Command.MarkFlagsMutuallyExclusive("redeliver", "since")
+	cmd.Command.MarkFlagsMutuallyExclusive("redeliver", "keep-webhook")
+	cmd.Command.MarkFlagsMutuallyExclusive("redeliver", "route")
 
 	return cmd
 }
 
 // WebhookProxy represents the command for proxying notifications to a local URL
 type WebhookProxy struct {
-	Client                   ChunkifyClientInterface // Client to use to create the webhook
-	localUrl                 string                  // Target URL to proxy notifications to
-	webhookSecret            string                  // Key used to sign proxied notifications
-	WebhookId                string                  // ID of the webhook receiving notifications
-	Events                   []string                // List of event types to proxy
-	CreatedGte               time.Time               // Filter for notifications created after this time
-	mut                      sync.Mutex              // Mutex for thread-safe access to shared resources
-	lastProxiedNotifications []chunkify.Notification // Tracks the 10 last proxied notifications
+	Client         ChunkifyClientInterface // Client to use to create the webhook
+	localUrl       string                  // Target URL to proxy notifications to
+	routes         []*Route                // Routes of the notifications, by event
+	webhookSecret  string                  // Key used to sign proxied notifications
+	WebhookId      string                  // ID of the webhook receiving notifications
+	Events         []string                // List of event types to proxy
+	Cursor         Cursor                  // Last forwarded notification, the next ones are created at or after it
+	cursorFile     string                  // File where the cursor is saved, not saved when empty
+	mut            sync.Mutex              // Mutex for thread-safe access to shared resources
+	seen           map[string]bool         // Notifications forwarded in the same second as the cursor
+	retries        []retry                 // Failed deliveries waiting for their next attempt
+	deadLetterFile string                  // File where the deliveries are written when the retries are exhausted
 }
 
-// Update handles incoming messages and updates the model state
+// Run forwards the notifications since the cursor, then polls the new ones and retries the failed
+// deliveries until ctx is done. The deliveries still waiting for a retry are then dead lettered
 func (r *WebhookProxy) Run(ctx context.Context) error {
-	notificationsChan := make(chan []chunkify.Notification, 100)
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	defer func() { r.flushRetries(time.Now()) }()
 
 	for {
+		notifications, err := r.Execute(ctx)
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("Error fetching notifications: %s\n", err)
+		}
+		for _, notif := range notifications {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.httpProxy(notif)
+		}
+		r.retryDue(time.Now())
+
 		select {
 		case <-ticker.C:
-			notifications, err := r.Execute(ctx)
-			if err != nil {
-				fmt.Printf("Error fetching notifications: %s\n", err)
-			}
-			notificationsChan <- notifications
-		case notifications := <-notificationsChan:
-			for _, notif := range notifications {
-				r.httpProxy(notif)
-			}
 		case <-ctx.Done():
 			return ctx.Err()
+		}
+	}
+}
+
+// startCursor sets where the forwarding starts: --since when given, after the saved cursor
+// if it's the one of the same webhook, or now. The cursor is then saved to path after each forwarded notification
+func (r *WebhookProxy) startCursor(path string, since string, now time.Time) error {
+	r.cursorFile = path
+	r.seen = map[string]bool{}
+
+	if since != "" {
+		t, err := formatter.ParseTime(since, now)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		r.Cursor = Cursor{WebhookID: r.WebhookId, CreatedAt: t}
+		return nil
+	}
 
+	saved, err := LoadCursor(path)
+	if err != nil {
+		return err
+	}
+	// the notifications of a deleted webhook can't be listed anymore
+	if saved != nil && saved.WebhookID == r.WebhookId {
+		r.Cursor = *saved
+		r.seen[saved.NotificationID] = true
+		for _, id := range saved.Seen {
+			r.seen[id] = true
 		}
+		return nil
 	}
+
+	r.Cursor = Cursor{WebhookID: r.WebhookId, CreatedAt: now}
+	return nil
 }
 
-// toParams converts ProxyCmd fields to NotificationListParams
+// toParams returns the params listing the notifications of the webhook since the cursor, oldest first
 func (r *WebhookProxy) toParams() chunkify.NotificationListParams {
-	limit := int64(10)
 	params := chunkify.NotificationListParams{
 		WebhookID: chunkify.String(r.WebhookId),
-		Limit:     chunkify.Int(limit),
+		Created:   chunkify.NotificationListParamsCreated{Sort: "asc"},
 	}
 
-	if len(r.lastProxiedNotifications) > 0 {
-		createdGte := r.lastProxiedNotifications[len(r.lastProxiedNotifications)-1].CreatedAt.Unix()
-		params.Created.Gte = chunkify.Int(createdGte)
+	if !r.Cursor.CreatedAt.IsZero() {
+		params.Created.Gte = chunkify.Int(r.Cursor.CreatedAt.Unix())
 	}
 
 	return params
@@ -188,7 +343,8 @@ func (r *WebhookProxy) toParams() chunkify.NotificationListParams {
 
 // Execute fetches notifications from the API based on the command parameters
 func (r *WebhookProxy) Execute(ctx context.Context) ([]chunkify.Notification, error) {
-	notifications, err := r.Client.NotificationList(ctx, r.toParams())
+	// all the pages, more notifications than a page may have been created since the last poll
+	notifications, err := ListNotifications(ctx, r.Client, r.toParams(), 0)
 	if err != nil {
 		return nil, err
 	}
@@ -213,18 +369,33 @@ func (r *WebhookProxy) httpProxy(notif chunkify.Notification) {
 		return
 	}
 
+	// the cursor moves on even if the delivery fails, the notification is retried from the queue
+	defer r.advanceCursor(notif)
+
+	if len(r.routes) == 0 {
+		r.routes = []*Route{{Pattern: "*", URL: r.localUrl}}
+	}
+	for _, route := range r.routes {
+		if route.Match(string(notif.Event)) {
+			r.attempt(retry{notif: notif, route: route}, time.Now())
+		}
+	}
+}
+
+// forwardNotification posts the payload of the notification to url, signed with the webhook secret.
+// It returns the status code of the response
+func forwardNotification(url string, webhookSecret string, notif chunkify.Notification) (int, error) {
 	buf := bytes.NewBufferString(notif.Payload)
-	req, err := http.NewRequest("POST", r.localUrl, buf)
+	req, err := http.NewRequest("POST", url, buf)
 	if err != nil {
-		fmt.Printf("Error creating http request:" + err.Error())
-		return
+		return 0, fmt.Errorf("error creating http request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "chunkify-cli/webhook-proxy")
 
 	timestamp := time.Now()
-	signature := generateSignature(notif.ID, timestamp, notif.Payload, r.webhookSecret)
+	signature := generateSignature(notif.ID, timestamp, notif.Payload, webhookSecret)
 	req.Header.Set("webhook-signature", signature)
 	req.Header.Set("webhook-id", notif.ID)
 	req.Header.Set("webhook-timestamp", fmt.Sprintf("%d", timestamp.Unix()))
@@ -233,16 +404,43 @@ func (r *WebhookProxy) httpProxy(notif chunkify.Notification) {
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("Request error: " + err.Error())
-		return
+		return 0, err
 	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
 
-	fmt.Printf("  [%d %s] %s %s (%s)\n",
-		resp.StatusCode,
-		http.StatusText(resp.StatusCode),
+func printForwarded(w io.Writer, statusCode int, notif chunkify.Notification, target string) {
+	fmt.Fprintf(w, "  [%d %s] %s %s (%s)%s\n",
+		statusCode,
+		http.StatusText(statusCode),
 		notif.ID,
 		notif.Event,
-		notif.ObjectID)
+		notif.ObjectID,
+		targetSuffix(target))
+}
+
+// localDevWebhook returns the webhook left by a previous listen with --keep-webhook, and true, or creates it.
+// The events of a reused webhook are replaced by the ones of this listen
+func (r *WebhookProxy) localDevWebhook(ctx context.Context, webhookUrl string) (chunkify.Webhook, bool, error) {
+	webhooks, err := r.Client.WebhookList(ctx)
+	if err != nil {
+		return chunkify.Webhook{}, false, fmt.Errorf("error listing webhooks: %w", err)
+	}
+
+	for _, wh := range webhooks {
+		if wh.URL != webhookUrl {
+			continue
+		}
+		if err := r.Client.WebhookUpdate(ctx, wh.ID, chunkify.WebhookUpdateParams{Events: r.Events, Enabled: chunkify.Bool(true)}); err != nil {
+			return chunkify.Webhook{}, false, fmt.Errorf("error updating webhook %s: %w", wh.ID, err)
+		}
+		return wh, true, nil
+	}
+
+	wh, err := r.createLocaldevWebhook(ctx, webhookUrl)
+	return wh, false, err
 }
 
 // createLocaldevWebhook sets up a webhook for local development
@@ -260,52 +458,57 @@ func (r *WebhookProxy) createLocaldevWebhook(ctx context.Context, webhookUrl str
 // deleteLocalDevWebhook removes the local development webhook
 func (r *WebhookProxy) deleteLocalDevWebhook(ctx context.Context, webhookId string) error {
 	if err := r.Client.WebhookDelete(ctx, webhookId); err != nil {
-		fmt.Printf("Couldn't delete localdev webhook. You need to manually delete it. webhookId: %s, error: %s\n", webhookId, err)
+		fmt.Printf("Couldn't delete localdev webhook %s: %s\nRun `chunkify webhooks prune-localdev` to delete it.\n", webhookId, err)
 		return err
 	}
 
 	return nil
 }
 
+// shouldProxy returns false for the notifications created before the cursor or already forwarded
 func (r *WebhookProxy) shouldProxy(notif chunkify.Notification) bool {
 	r.mut.Lock()
 	defer r.mut.Unlock()
 
-	for _, n := range r.lastProxiedNotifications {
-		if n.ID == notif.ID {
-			return false
-		}
+	// the API filters by second, the notifications of the cursor's second are listed again
+	created, cursor := notif.CreatedAt.Unix(), r.Cursor.CreatedAt.Unix()
+	if r.Cursor.CreatedAt.IsZero() {
+		return !r.seen[notif.ID]
 	}
+	return created > cursor || (created == cursor && !r.seen[notif.ID])
+}
 
-	// Add this notification to the list
-	r.lastProxiedNotifications = append(r.lastProxiedNotifications, notif)
-	if len(r.lastProxiedNotifications) > 10 {
-		r.lastProxiedNotifications = r.lastProxiedNotifications[1:]
+// advanceCursor moves the cursor to the forwarded notification and saves it
+func (r *WebhookProxy) advanceCursor(notif chunkify.Notification) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.seen == nil || notif.CreatedAt.Unix() != r.Cursor.CreatedAt.Unix() {
+		r.seen = map[string]bool{}
+	}
+	r.seen[notif.ID] = true
+	r.Cursor = Cursor{WebhookID: r.WebhookId, NotificationID: notif.ID, CreatedAt: notif.CreatedAt}
+	for id := range r.seen {
+		r.Cursor.Seen = append(r.Cursor.Seen, id)
+	}
+	slices.Sort(r.Cursor.Seen)
+
+	if r.cursorFile == "" {
+		return
+	}
+	if err := SaveCursor(r.cursorFile, r.Cursor); err != nil {
+		fmt.Printf("Error saving the cursor: %s\n", err)
 	}
-	return true
 }
 
 // generateSignature creates an HMAC signature for the payload using the secret key
 // Following the Standard Webhooks specification: signs "msgId.timestamp.payload"
 func generateSignature(id string, timestamp time.Time, payloadString string, secretKey string) string {
-	// Remove prefix
-	secret := strings.TrimPrefix(secretKey, "whsec_")
-
-	secretBytes, err := base64.StdEncoding.DecodeString(secret)
+	verifier, err := verify.New(secretKey)
 	if err != nil {
 		fmt.Printf("Error decoding secret: %v\n", err)
 		return ""
 	}
 
-	// Create the string to sign
-	msgToSign := fmt.Sprintf("%s.%d.%s", id, timestamp.Unix(), payloadString)
-
-	h := hmac.New(sha256.New, secretBytes)
-	h.Write([]byte(msgToSign))
-
-	// Use base64 encoding instead of hex
-	sig := base64.StdEncoding.EncodeToString(h.Sum(nil))
-
-	// Return with v1 prefix
-	return "v1," + sig
+	return verifier.Sign(id, timestamp, []byte(payloadString))
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -26,7 +27,40 @@ func (m *MockChunkifyClient) NotificationList(ctx context.Context, params chunki
 	if m.listError != nil {
 		return nil, m.listError
 	}
-	return m.notifications, nil
+	notifications := []chunkify.Notification{}
+	for _, notif := range m.notifications {
+		if !params.Created.Gte.Valid() || notif.CreatedAt.Unix() >= params.Created.Gte.Value {
+			notifications = append(notifications, notif)
+		}
+	}
+	if params.Offset.Valid() {
+		notifications = notifications[min(int(params.Offset.Value), len(notifications)):]
+	}
+	if params.Limit.Valid() {
+		notifications = notifications[:min(int(params.Limit.Value), len(notifications))]
+	}
+	return notifications, nil
+}
+
+func (m *MockChunkifyClient) NotificationGet(ctx context.Context, notificationId string) (*chunkify.Notification, error) {
+	for _, notif := range m.notifications {
+		if notif.ID == notificationId {
+			return &notif, nil
+		}
+	}
+	return nil, fmt.Errorf("notification not found")
+}
+
+func (m *MockChunkifyClient) WebhookList(ctx context.Context) ([]chunkify.Webhook, error) {
+	if m.listError != nil {
+		return nil, m.listError
+	}
+	webhooks := []chunkify.Webhook{}
+	for _, wh := range m.webhooks {
+		webhooks = append(webhooks, wh)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
 }
 
 func (m *MockChunkifyClient) WebhookCreate(ctx context.Context, params chunkify.WebhookNewParams) (*chunkify.Webhook, error) {
@@ -42,6 +76,21 @@ func (m *MockChunkifyClient) WebhookCreate(ctx context.Context, params chunkify.
 	return &webhook, nil
 }
 
+func (m *MockChunkifyClient) WebhookUpdate(ctx context.Context, webhookId string, params chunkify.WebhookUpdateParams) error {
+	wh, ok := m.webhooks[webhookId]
+	if !ok {
+		return fmt.Errorf("webhook not found")
+	}
+	if params.Enabled.Valid() {
+		wh.Enabled = params.Enabled.Value
+	}
+	if len(params.Events) > 0 {
+		wh.Events = params.Events
+	}
+	m.webhooks[webhookId] = wh
+	return nil
+}
+
 func (m *MockChunkifyClient) WebhookDelete(ctx context.Context, webhookId string) error {
 	if m.deleteError != nil {
 		return m.deleteError
@@ -61,28 +110,26 @@ func TestWebhookProxy_ToParams(t *testing.T) {
 		t.Errorf("Expected WebhookId to be 'wh_webhookid', got %v", params.WebhookID.Value)
 	}
 
-	if params.Limit.Value != 10 {
-		t.Errorf("Expected Limit to be 10, got %v", params.Limit.Value)
+	if params.Created.Sort != "asc" {
+		t.Errorf("Expected the oldest notifications first, got sort %q", params.Created.Sort)
+	}
+
+	if params.Created.Gte.Valid() {
+		t.Errorf("Expected no CreatedGte without cursor, got %d", params.Created.Gte.Value)
 	}
 }
 
-func TestWebhookProxy_ToParams_WithLastNotification(t *testing.T) {
+func TestWebhookProxy_ToParams_WithCursor(t *testing.T) {
 	proxy := &WebhookProxy{
 		WebhookId: "wh_webhookid",
-		lastProxiedNotifications: []chunkify.Notification{
-			{
-				ID:        "notf_notifid",
-				CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
-			},
+		Cursor: Cursor{
+			NotificationID: "notf_notifid",
+			CreatedAt:      time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
 		},
 	}
 
 	params := proxy.toParams()
 
-	if params.Created.Gte.Value == 0 {
-		t.Error("Expected CreatedGte to be set when lastProxiedNotifications is not empty")
-	}
-
 	expectedTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC).Unix()
 	if params.Created.Gte.Value != expectedTime {
 		t.Errorf("Expected CreatedGte to be %d, got %d", expectedTime, params.Created.Gte.Value)
@@ -140,9 +187,7 @@ func TestWebhookProxy_Execute_AllEvents(t *testing.T) {
 }
 
 func TestWebhookProxy_ShouldProxy(t *testing.T) {
-	proxy := &WebhookProxy{
-		lastProxiedNotifications: []chunkify.Notification{},
-	}
+	proxy := &WebhookProxy{}
 
 	notif := chunkify.Notification{ID: "notf_notifid1"}
 
@@ -150,6 +195,7 @@ func TestWebhookProxy_ShouldProxy(t *testing.T) {
 	if !proxy.shouldProxy(notif) {
 		t.Error("Expected shouldProxy to return true for new notification")
 	}
+	proxy.advanceCursor(notif)
 
 	// Second time should not proxy (already seen)
 	if proxy.shouldProxy(notif) {
@@ -157,29 +203,34 @@ func TestWebhookProxy_ShouldProxy(t *testing.T) {
 	}
 }
 
-func TestWebhookProxy_ShouldProxy_MaxNotifications(t *testing.T) {
-	proxy := &WebhookProxy{
-		lastProxiedNotifications: []chunkify.Notification{},
-	}
+func TestWebhookProxy_ShouldProxy_Cursor(t *testing.T) {
+	cursorTime := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	proxy := &WebhookProxy{}
+	proxy.advanceCursor(chunkify.Notification{ID: "notf_1", CreatedAt: cursorTime})
 
-	// Add 10 notifications
-	for i := 0; i < 10; i++ {
-		notif := chunkify.Notification{ID: "notif_notifid" + string(rune(i))}
-		proxy.shouldProxy(notif)
+	tests := []struct {
+		name  string
+		notif chunkify.Notification
+		want  bool
+	}{
+		{name: "before the cursor", notif: chunkify.Notification{ID: "notf_0", CreatedAt: cursorTime.Add(-time.Second)}, want: false},
+		{name: "cursor", notif: chunkify.Notification{ID: "notf_1", CreatedAt: cursorTime}, want: false},
+		{name: "same second as the cursor", notif: chunkify.Notification{ID: "notf_2", CreatedAt: cursorTime.Add(500 * time.Millisecond)}, want: true},
+		{name: "after the cursor", notif: chunkify.Notification{ID: "notf_3", CreatedAt: cursorTime.Add(time.Minute)}, want: true},
 	}
 
-	// Add 11th notification - should remove the first one
-	notif11 := chunkify.Notification{ID: "notif_notifid11"}
-	proxy.shouldProxy(notif11)
-
-	if len(proxy.lastProxiedNotifications) != 10 {
-		t.Errorf("Expected 10 notifications in list, got %d", len(proxy.lastProxiedNotifications))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proxy.shouldProxy(tt.notif); got != tt.want {
+				t.Errorf("shouldProxy() = %v, want %v", got, tt.want)
+			}
+		})
 	}
 
-	// First notification should be gone
-	notif0 := chunkify.Notification{ID: "notif_notifid0"}
-	if !proxy.shouldProxy(notif0) {
-		t.Error("Expected shouldProxy to return true for notification that was removed from list")
+	// the notifications of the cursor's second are forgotten once the cursor moves to the next second
+	proxy.advanceCursor(chunkify.Notification{ID: "notf_3", CreatedAt: cursorTime.Add(time.Minute)})
+	if len(proxy.seen) != 1 || !proxy.seen["notf_3"] {
+		t.Errorf("Expected only notf_3 to be remembered, got %v", proxy.seen)
 	}
 }
 
@@ -254,9 +305,8 @@ func TestWebhookProxy_HttpProxy(t *testing.T) {
 	secretWithPrefix := "whsec_" + secretBase64
 
 	proxy := &WebhookProxy{
-		localUrl:                 server.URL,
-		webhookSecret:            secretWithPrefix,
-		lastProxiedNotifications: []chunkify.Notification{},
+		localUrl:      server.URL,
+		webhookSecret: secretWithPrefix,
 	}
 
 	timestamp := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -345,9 +395,7 @@ func TestWebhookProxy_HttpProxy_ShouldNotProxy(t *testing.T) {
 	proxy := &WebhookProxy{
 		localUrl:      server.URL,
 		webhookSecret: "test-secret",
-		lastProxiedNotifications: []chunkify.Notification{
-			{ID: "notf_test123"}, // Already seen notification
-		},
+		seen:          map[string]bool{"notf_test123": true}, // Already seen notification
 	}
 
 	notif := chunkify.Notification{
@@ -362,3 +410,30 @@ func TestWebhookProxy_HttpProxy_ShouldNotProxy(t *testing.T) {
 
 	// The test will fail if the HTTP handler is called due to the t.Error() in the handler
 }
+
+func TestWebhookProxy_LocalDevWebhook(t *testing.T) {
+	mockClient := &MockChunkifyClient{webhooks: map[string]chunkify.Webhook{
+		"wh_kept":  {ID: "wh_kept", URL: localDevWebhookURL("mac"), Events: []string{"job.failed"}},
+		"wh_other": {ID: "wh_other", URL: "https://example.com/webhooks", Enabled: true},
+	}}
+	proxy := &WebhookProxy{Client: mockClient, Events: []string{"job.completed", "job.failed"}}
+
+	webhook, reused, err := proxy.localDevWebhook(context.Background(), localDevWebhookURL("mac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if webhook.ID != "wh_kept" || !reused {
+		t.Errorf("Expected the kept webhook to be reused, got %s", webhook.ID)
+	}
+	if kept := mockClient.webhooks["wh_kept"]; !kept.Enabled || len(kept.Events) != 2 {
+		t.Errorf("Expected the kept webhook to be enabled with the events, got %+v", kept)
+	}
+
+	webhook, reused, err = proxy.localDevWebhook(context.Background(), localDevWebhookURL("linux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if webhook.ID != "wh_webhookid" || reused || webhook.URL != localDevWebhookURL("linux") {
+		t.Errorf("Expected a new webhook, got %+v", webhook)
+	}
+}
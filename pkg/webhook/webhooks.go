@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+	"github.com/chunkifydev/cli/pkg/config"
+	"github.com/chunkifydev/cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+const indent = "  "
+
+// localDevDomain is the domain of the webhooks created by listen, named after the host
+const localDevDomain = "chunkify.local"
+
+// NewWebhooksCommand creates and configures a new webhooks root command
+func NewWebhooksCommand(cfg *config.Config) *Command {
+	cmd := &Command{
+		Config: cfg,
+		Command: &cobra.Command{
+			Use:   "webhooks",
+			Short: "Manage the webhooks of the project",
+			Long: `Create, update and delete the webhooks of the project
+
+Examples:
+
+chunkify webhooks list
+chunkify webhooks create --url https://example.com/webhooks/chunkify --events job.completed,job.failed
+chunkify webhooks update wh_2G6MJiNz71bHQGNzGwKx5cJwPFS --disable
+chunkify webhooks delete wh_2G6MJiNz71bHQGNzGwKx5cJwPFS
+chunkify webhooks prune-localdev
+`,
+		},
+	}
+
+	cmd.Command.AddCommand(newWebhooksListCommand(cfg))
+	cmd.Command.AddCommand(newWebhooksCreateCommand(cfg))
+	cmd.Command.AddCommand(newWebhooksUpdateCommand(cfg))
+	cmd.Command.AddCommand(newWebhooksDeleteCommand(cfg))
+	cmd.Command.AddCommand(newWebhooksPruneLocalDevCommand(cfg))
+
+	return cmd
+}
+
+func newWebhooksListCommand(cfg *config.Config) *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the webhooks",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			webhooks, err := client.WebhookList(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error listing webhooks: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, webhooks)
+			}
+			return printWebhooks(os.Stdout, webhooks)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newWebhooksCreateCommand(cfg *config.Config) *cobra.Command {
+	var (
+		webhookUrl string
+		events     []string
+		disabled   bool
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a webhook",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if u, err := url.Parse(webhookUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("--url must be an HTTP URL: %s", webhookUrl)
+			}
+			return validateEvents(events)
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			webhook, err := client.WebhookCreate(cmd.Context(), chunkify.WebhookNewParams{
+				URL:     webhookUrl,
+				Events:  events,
+				Enabled: chunkify.Bool(!disabled),
+			})
+			if err != nil {
+				return fmt.Errorf("error creating webhook: %w", err)
+			}
+
+			if jsonOutput {
+				return formatter.PrintJSON(os.Stdout, webhook)
+			}
+			fmt.Printf("%sWebhook %s created\n", indent, webhook.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&webhookUrl, "url", "", "The URL receiving the notifications")
+	cmd.Flags().StringSliceVar(&events, "events", allEvents, "The events sent to the webhook: "+strings.Join(allEvents, ", "))
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the webhook disabled")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.MarkFlagRequired("url")
+
+	return cmd
+}
+
+func newWebhooksUpdateCommand(cfg *config.Config) *cobra.Command {
+	var (
+		enable  bool
+		disable bool
+		events  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update <wh_id>",
+		Short: "Enable, disable or change the events of a webhook",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !enable && !disable && !cmd.Flags().Changed("events") {
+				return fmt.Errorf("nothing to update, use --enable, --disable or --events")
+			}
+			return validateEvents(events)
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := chunkify.WebhookUpdateParams{Events: events}
+			if enable || disable {
+				params.Enabled = chunkify.Bool(enable)
+			}
+
+			client := &ChunkifyClient{Client: cfg.Client}
+			if err := client.WebhookUpdate(cmd.Context(), args[0], params); err != nil {
+				return fmt.Errorf("error updating webhook %s: %w", args[0], err)
+			}
+			fmt.Printf("%sWebhook %s updated\n", indent, args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&enable, "enable", false, "Enable the webhook")
+	cmd.Flags().BoolVar(&disable, "disable", false, "Disable the webhook")
+	cmd.Flags().StringSliceVar(&events, "events", nil, "Replace the events sent to the webhook: "+strings.Join(allEvents, ", "))
+	cmd.MarkFlagsMutuallyExclusive("enable", "disable")
+
+	return cmd
+}
+
+func newWebhooksDeleteCommand(cfg *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:          "delete <wh_id>...",
+		Short:        "Delete one or more webhooks",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+
+			errs := []error{}
+			for _, id := range args {
+				if err := client.WebhookDelete(cmd.Context(), id); err != nil {
+					errs = append(errs, fmt.Errorf("error deleting webhook %s: %w", id, err))
+					continue
+				}
+				fmt.Printf("%sWebhook %s deleted\n", indent, id)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func newWebhooksPruneLocalDevCommand(cfg *config.Config) *cobra.Command {
+	var (
+		hostname string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune-localdev",
+		Short: "Delete the leftover webhooks of chunkify listen",
+		Long: `Delete the leftover webhooks of chunkify listen
+
+chunkify listen creates a http://<hostname>.` + localDevDomain + ` webhook and deletes it on exit.
+When the deletion fails, e.g. the process was killed, the webhook stays in the project.
+Beware that the webhooks of the listen commands still running are deleted too, use --hostname to only delete the ones of a machine.`,
+		Example:      "chunkify webhooks prune-localdev --hostname mac.home",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := &ChunkifyClient{Client: cfg.Client}
+			return pruneLocalDevWebhooks(cmd.Context(), client, os.Stdout, hostname, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&hostname, "hostname", "", "Only delete the localdev webhook of the given hostname")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the webhooks that would be deleted without deleting them")
+
+	return cmd
+}
+
+// pruneLocalDevWebhooks deletes the localdev webhooks, only the one of hostname if set
+func pruneLocalDevWebhooks(ctx context.Context, client ChunkifyClientInterface, w io.Writer, hostname string, dryRun bool) error {
+	webhooks, err := client.WebhookList(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing webhooks: %w", err)
+	}
+
+	errs := []error{}
+	pruned := 0
+	for _, wh := range webhooks {
+		host, ok := localDevHostname(wh.URL)
+		if !ok || (hostname != "" && host != hostname) {
+			continue
+		}
+		pruned++
+
+		if dryRun {
+			fmt.Fprintf(w, "%sWebhook %s (%s) would be deleted\n", indent, wh.ID, wh.URL)
+			continue
+		}
+		if err := client.WebhookDelete(ctx, wh.ID); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting webhook %s: %w", wh.ID, err))
+			continue
+		}
+		fmt.Fprintf(w, "%sWebhook %s (%s) deleted\n", indent, wh.ID, wh.URL)
+	}
+
+	if pruned == 0 {
+		fmt.Fprintln(w, indent+"No localdev webhook found")
+	}
+	return errors.Join(errs...)
+}
+
+// localDevWebhookURL returns the URL of the webhook created by listen for hostname
+func localDevWebhookURL(hostname string) string {
+	return fmt.Sprintf("http://%s.%s", hostname, localDevDomain)
+}
+
+// localDevHostname returns the hostname of a webhook created by listen
+func localDevHostname(webhookUrl string) (string, bool) {
+	u, err := url.Parse(webhookUrl)
+	if err != nil || u.Scheme != "http" {
+		return "", false
+	}
+	host, ok := strings.CutSuffix(u.Host, "."+localDevDomain)
+	if !ok || host == "" {
+		return "", false
+	}
+	return host, true
+}
+
+func validateEvents(events []string) error {
+	for _, event := range events {
+		if !slices.Contains(allEvents, event) {
+			return fmt.Errorf("invalid event: %s. Valid events are %s", event, strings.Join(allEvents, ", "))
+		}
+	}
+	return nil
+}
+
+func printWebhooks(w io.Writer, webhooks []chunkify.Webhook) error {
+	if len(webhooks) == 0 {
+		fmt.Fprintln(w, indent+"No webhook found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, indent+"ID\tURL\tENABLED\tEVENTS")
+	for _, wh := range webhooks {
+		fmt.Fprintf(tw, "%s%s\t%s\t%t\t%s\n", indent, wh.ID, wh.URL, wh.Enabled, strings.Join(wh.Events, ","))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%sTotal: %d\n", indent, len(webhooks))
+	return nil
+}
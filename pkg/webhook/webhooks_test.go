@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	chunkify "github.com/chunkifydev/chunkify-go"
+)
+
+func TestLocalDevHostname(t *testing.T) {
+	tests := []struct {
+		url      string
+		hostname string
+		ok       bool
+	}{
+		{url: localDevWebhookURL("mac.home"), hostname: "mac.home", ok: true},
+		{url: "http://chunkify.local"},
+		{url: "https://mac.home.chunkify.local"},
+		{url: "https://example.com/webhooks/chunkify"},
+	}
+
+	for _, tt := range tests {
+		hostname, ok := localDevHostname(tt.url)
+		if hostname != tt.hostname || ok != tt.ok {
+			t.Errorf("%s: expected %q, %t, got %q, %t", tt.url, tt.hostname, tt.ok, hostname, ok)
+		}
+	}
+}
+
+func TestPruneLocalDevWebhooks(t *testing.T) {
+	newClient := func() *MockChunkifyClient {
+		return &MockChunkifyClient{webhooks: map[string]chunkify.Webhook{
+			"wh_app":   {ID: "wh_app", URL: "https://example.com/webhooks/chunkify"},
+			"wh_mac":   {ID: "wh_mac", URL: "http://mac.home.chunkify.local"},
+			"wh_linux": {ID: "wh_linux", URL: "http://linux.chunkify.local"},
+		}}
+	}
+
+	client := newClient()
+	var buf bytes.Buffer
+	if err := pruneLocalDevWebhooks(context.Background(), client, &buf, "", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.webhooks) != 3 || strings.Count(buf.String(), "would be deleted") != 2 {
+		t.Errorf("expected nothing to be deleted with --dry-run, got:\n%s", buf.String())
+	}
+
+	if err := pruneLocalDevWebhooks(context.Background(), client, &buf, "mac.home", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.webhooks["wh_mac"]; ok || len(client.webhooks) != 2 {
+		t.Errorf("expected only wh_mac to be deleted, got %v", client.webhooks)
+	}
+
+	client = newClient()
+	if err := pruneLocalDevWebhooks(context.Background(), client, &buf, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.webhooks["wh_app"]; !ok || len(client.webhooks) != 1 {
+		t.Errorf("expected only wh_app to be kept, got %v", client.webhooks)
+	}
+}
+
+func TestValidateEvents(t *testing.T) {
+	if err := validateEvents([]string{"job.completed", "upload.failed"}); err != nil {
+		t.Error(err)
+	}
+	if err := validateEvents([]string{"job.started"}); err == nil || !strings.Contains(err.Error(), "invalid event: job.started") {
+		t.Errorf("expected an invalid event error, got %v", err)
+	}
+}
+
+func TestPrintWebhooks(t *testing.T) {
+	var buf bytes.Buffer
+	webhooks := []chunkify.Webhook{{ID: "wh_1", URL: "https://example.com", Enabled: true, Events: []string{"job.completed", "job.failed"}}}
+	if err := printWebhooks(&buf, webhooks); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"wh_1", "https://example.com", "true", "job.completed,job.failed", "Total: 1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in the output:\n%s", want, buf.String())
+		}
+	}
+}